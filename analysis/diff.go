@@ -0,0 +1,137 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+// Change represents a single test that differs between two runs.
+type Change struct {
+	// Key identifies the test.
+	Key Key `json:"key" yaml:"key"`
+
+	// Base is the test from the base run, or nil if the test was absent.
+	Base *junit.Test `json:"base,omitempty" yaml:"base,omitempty"`
+
+	// Head is the test from the head run, or nil if the test was absent.
+	Head *junit.Test `json:"head,omitempty" yaml:"head,omitempty"`
+}
+
+// Report contains every test that differs between two runs, by category. A
+// test that is added or removed is not otherwise categorized.
+type Report struct {
+	// NewlyFailing is every test that failed or errored in the head run, but
+	// had passed or been skipped in the base run.
+	NewlyFailing []Change `json:"newly_failing,omitempty" yaml:"newly_failing,omitempty"`
+
+	// NewlyPassing is every test that passed in the head run, but had failed
+	// or errored in the base run.
+	NewlyPassing []Change `json:"newly_passing,omitempty" yaml:"newly_passing,omitempty"`
+
+	// Added is every test that is present in the head run, but was absent
+	// from the base run.
+	Added []Change `json:"added,omitempty" yaml:"added,omitempty"`
+
+	// Removed is every test that was present in the base run, but is absent
+	// from the head run.
+	Removed []Change `json:"removed,omitempty" yaml:"removed,omitempty"`
+
+	// Slower is every test that passed in both runs, but took significantly
+	// longer in the head run.
+	Slower []Change `json:"slower,omitempty" yaml:"slower,omitempty"`
+}
+
+// Regressed reports if any test started failing or became slower.
+func (r Report) Regressed() bool {
+	return len(r.NewlyFailing) > 0 || len(r.Slower) > 0
+}
+
+// DiffOption configures the behavior of Diff.
+type DiffOption func(*diffOptions)
+
+type diffOptions struct {
+	ratio   float64
+	minimum time.Duration
+}
+
+// WithSlowdown configures when a test is considered to be significantly
+// slower. A test is slower if its duration grew by more than the given ratio
+// of its base duration, and by more than the given minimum duration. By
+// default, a test must have grown by 50%, and by 100ms.
+func WithSlowdown(ratio float64, minimum time.Duration) DiffOption {
+	return func(config *diffOptions) {
+		config.ratio = ratio
+		config.minimum = minimum
+	}
+}
+
+// Diff matches the tests in both given runs, including those in nested
+// suites, and returns a report of every test that differs between them.
+// Changes are ordered as the tests appear in the head run, except for removed
+// tests, which are ordered as they appear in the base run.
+func Diff(base, head []junit.Suite, opts ...DiffOption) Report {
+	config := diffOptions{
+		ratio:   0.5,
+		minimum: 100 * time.Millisecond,
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	var (
+		report               = Report{}
+		baseTests, baseOrder = index(base)
+		headTests, headOrder = index(head)
+	)
+
+	for _, key := range headOrder {
+		headTest := headTests[key]
+
+		baseTest, found := baseTests[key]
+		if !found {
+			report.Added = append(report.Added, Change{Key: key, Head: &headTest})
+
+			continue
+		}
+
+		change := Change{Key: key, Base: &baseTest, Head: &headTest}
+
+		switch {
+		case failing(headTest) && !failing(baseTest):
+			report.NewlyFailing = append(report.NewlyFailing, change)
+		case headTest.Status == junit.StatusPassed && failing(baseTest):
+			report.NewlyPassing = append(report.NewlyPassing, change)
+		case headTest.Status == junit.StatusPassed && baseTest.Status == junit.StatusPassed && config.slower(baseTest, headTest):
+			report.Slower = append(report.Slower, change)
+		}
+	}
+
+	for _, key := range baseOrder {
+		if _, found := headTests[key]; !found {
+			baseTest := baseTests[key]
+			report.Removed = append(report.Removed, Change{Key: key, Base: &baseTest})
+		}
+	}
+
+	return report
+}
+
+// slower reports if the head test took significantly longer than the base
+// test.
+func (config diffOptions) slower(base, head junit.Test) bool {
+	growth := head.Duration - base.Duration
+
+	return growth > config.minimum && float64(growth) > float64(base.Duration)*config.ratio
+}
+
+// failing reports if the given test resulted in a failure or an error.
+func failing(test junit.Test) bool {
+	return test.Status == junit.StatusFailed || test.Status == junit.StatusError
+}
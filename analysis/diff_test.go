@@ -0,0 +1,77 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+func TestDiff(t *testing.T) {
+	test := func(name string, status junit.Status, duration time.Duration) junit.Test {
+		return junit.Test{
+			Name:     name,
+			Status:   status,
+			Duration: duration,
+		}
+	}
+
+	base := []junit.Suite{{
+		Name: "suite",
+		Tests: []junit.Test{
+			test("unchanged", junit.StatusPassed, time.Second),
+			test("breaks", junit.StatusPassed, time.Second),
+			test("fixed", junit.StatusFailed, time.Second),
+			test("removed", junit.StatusPassed, time.Second),
+			test("slower", junit.StatusPassed, time.Second),
+			test("jitter", junit.StatusPassed, 10*time.Millisecond),
+		},
+	}}
+
+	head := []junit.Suite{{
+		Name: "suite",
+		Tests: []junit.Test{
+			test("added", junit.StatusFailed, time.Second),
+			test("unchanged", junit.StatusPassed, time.Second),
+			test("breaks", junit.StatusError, time.Second),
+			test("fixed", junit.StatusPassed, time.Second),
+			test("slower", junit.StatusPassed, 2*time.Second),
+			test("jitter", junit.StatusPassed, 90*time.Millisecond),
+		},
+	}}
+
+	names := func(changes []Change) []string {
+		var names []string
+		for _, change := range changes {
+			names = append(names, change.Key.Name)
+		}
+
+		return names
+	}
+
+	report := Diff(base, head)
+
+	assertEqual(t, []string{"breaks"}, names(report.NewlyFailing))
+	assertEqual(t, []string{"fixed"}, names(report.NewlyPassing))
+	assertEqual(t, []string{"added"}, names(report.Added))
+	assertEqual(t, []string{"removed"}, names(report.Removed))
+	assertEqual(t, []string{"slower"}, names(report.Slower))
+	assertEqual(t, true, report.Regressed())
+
+	assertEqual(t, (*junit.Test)(nil), report.Added[0].Base)
+	assertEqual(t, (*junit.Test)(nil), report.Removed[0].Head)
+	assertEqual(t, junit.StatusPassed, report.NewlyFailing[0].Base.Status)
+	assertEqual(t, junit.StatusError, report.NewlyFailing[0].Head.Status)
+
+	report = Diff(base, head, WithSlowdown(0.5, 0))
+	assertEqual(t, []string{"slower", "jitter"}, names(report.Slower))
+
+	report = Diff(base, base)
+	assertEqual(t, Report{}, report)
+	assertEqual(t, false, report.Regressed())
+}
@@ -0,0 +1,139 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"sort"
+
+	"github.com/joshdk/go-junit"
+)
+
+// History is the record of a single test across multiple runs.
+type History struct {
+	// Key identifies the test.
+	Key Key `json:"key" yaml:"key"`
+
+	// Statuses is the status of the test in each run, in the order that the
+	// runs were given. An empty status means that the test was absent from
+	// that run.
+	Statuses []junit.Status `json:"statuses" yaml:"statuses"`
+
+	// Runs is the number of runs in which the test was present, and was not
+	// skipped.
+	Runs int `json:"runs" yaml:"runs"`
+
+	// Passed is the number of runs in which the test passed.
+	Passed int `json:"passed" yaml:"passed"`
+
+	// Failed is the number of runs in which the test resulted in a failure or
+	// an error.
+	Failed int `json:"failed" yaml:"failed"`
+
+	// Flaky is the number of runs in which the test passed, but only after
+	// previously failing or erroring within that same run.
+	Flaky int `json:"flaky" yaml:"flaky"`
+
+	// Flips is the number of times that the test went from passing to failing,
+	// or from failing to passing, between consecutive runs. Runs in which the
+	// test was absent or skipped are ignored.
+	Flips int `json:"flips" yaml:"flips"`
+
+	// FlipRate is the fraction of consecutive runs in which the test flipped,
+	// between 0 and 1.
+	FlipRate float64 `json:"flip_rate" yaml:"flip_rate"`
+
+	// Score is the flakiness of the test, between 0 and 1. It is calculated as
+	// the number of flips and flaky runs, relative to the number of runs. A
+	// test that always passes, or always fails, has a score of 0.
+	Score float64 `json:"score" yaml:"score"`
+}
+
+// Flakiness correlates the tests across all of the given runs, and returns
+// the history of every test, ordered from most to least flaky. Tests with
+// equal scores retain the order in which they were first encountered.
+func Flakiness(runs [][]junit.Suite) []History {
+	var (
+		histories = make(map[Key]*History)
+		order     []Key
+	)
+
+	for run, suites := range runs {
+		tests, keys := index(suites)
+
+		for _, key := range keys {
+			history, found := histories[key]
+			if !found {
+				history = &History{
+					Key:      key,
+					Statuses: make([]junit.Status, len(runs)),
+				}
+				histories[key] = history
+				order = append(order, key)
+			}
+
+			test := tests[key]
+			history.Statuses[run] = test.Status
+
+			if test.Flaky() {
+				history.Flaky++
+			}
+		}
+	}
+
+	results := make([]History, len(order))
+	for index, key := range order {
+		history := histories[key]
+		history.tally()
+		results[index] = *history
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	return results
+}
+
+// tally calculates the counts, flips, and scores from the statuses of each
+// run.
+func (h *History) tally() {
+	var previous junit.Status
+
+	for _, status := range h.Statuses {
+		switch status {
+		case junit.StatusPassed:
+		case junit.StatusFailed, junit.StatusError:
+			// Failures and errors are treated alike.
+			status = junit.StatusFailed
+		default:
+			continue
+		}
+
+		h.Runs++
+		if status == junit.StatusPassed {
+			h.Passed++
+		} else {
+			h.Failed++
+		}
+
+		if previous != "" && previous != status {
+			h.Flips++
+		}
+
+		previous = status
+	}
+
+	if h.Runs > 1 {
+		h.FlipRate = float64(h.Flips) / float64(h.Runs-1)
+	}
+
+	if h.Runs > 0 {
+		h.Score = float64(h.Flips+h.Flaky) / float64(h.Runs)
+		if h.Score > 1 {
+			h.Score = 1
+		}
+	}
+}
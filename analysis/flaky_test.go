@@ -0,0 +1,112 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"testing"
+
+	"github.com/joshdk/go-junit"
+)
+
+// run returns a single run, with one nested suite containing tests with the
+// given names and statuses.
+func run(statuses map[string]junit.Status) []junit.Suite {
+	tests := make([]junit.Test, 0, len(statuses))
+	for _, name := range []string{"stable", "broken", "flipping", "retried", "skipped"} {
+		if status, found := statuses[name]; found {
+			test := junit.Test{
+				Name:      name,
+				Classname: "Example",
+				Status:    status,
+			}
+			if name == "retried" {
+				test.Attempts = []junit.Attempt{{Status: junit.StatusFailed, Rerun: true}}
+			}
+			tests = append(tests, test)
+		}
+	}
+
+	return []junit.Suite{{
+		Name: "outer",
+		Suites: []junit.Suite{{
+			Name:  "inner",
+			Tests: tests,
+		}},
+	}}
+}
+
+func TestFlakiness(t *testing.T) {
+	runs := [][]junit.Suite{
+		run(map[string]junit.Status{
+			"stable":   junit.StatusPassed,
+			"broken":   junit.StatusFailed,
+			"flipping": junit.StatusPassed,
+			"retried":  junit.StatusPassed,
+			"skipped":  junit.StatusSkipped,
+		}),
+		run(map[string]junit.Status{
+			"stable":   junit.StatusPassed,
+			"broken":   junit.StatusError,
+			"flipping": junit.StatusFailed,
+			"retried":  junit.StatusPassed,
+		}),
+		run(map[string]junit.Status{
+			"stable":   junit.StatusPassed,
+			"broken":   junit.StatusFailed,
+			"flipping": junit.StatusPassed,
+			"skipped":  junit.StatusSkipped,
+		}),
+	}
+
+	key := func(name string) Key {
+		return Key{Suite: "outer/inner", Classname: "Example", Name: name}
+	}
+
+	expected := []History{
+		{
+			Key:      key("retried"),
+			Statuses: []junit.Status{junit.StatusPassed, junit.StatusPassed, ""},
+			Runs:     2,
+			Passed:   2,
+			Flaky:    2,
+			Score:    1,
+		},
+		{
+			Key:      key("flipping"),
+			Statuses: []junit.Status{junit.StatusPassed, junit.StatusFailed, junit.StatusPassed},
+			Runs:     3,
+			Passed:   2,
+			Failed:   1,
+			Flips:    2,
+			FlipRate: 1,
+			Score:    2.0 / 3.0,
+		},
+		{
+			Key:      key("stable"),
+			Statuses: []junit.Status{junit.StatusPassed, junit.StatusPassed, junit.StatusPassed},
+			Runs:     3,
+			Passed:   3,
+		},
+		{
+			Key:      key("broken"),
+			Statuses: []junit.Status{junit.StatusFailed, junit.StatusError, junit.StatusFailed},
+			Runs:     3,
+			Failed:   3,
+		},
+		{
+			Key:      key("skipped"),
+			Statuses: []junit.Status{junit.StatusSkipped, "", junit.StatusSkipped},
+		},
+	}
+
+	actual := Flakiness(runs)
+	assertEqual(t, expected, actual)
+}
+
+func TestKeyString(t *testing.T) {
+	assertEqual(t, "outer/inner > Example > test", Key{Suite: "outer/inner", Classname: "Example", Name: "test"}.String())
+	assertEqual(t, "test", Key{Name: "test"}.String())
+}
@@ -0,0 +1,79 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+// Package analysis exposes several library functions for comparing and
+// correlating the results of multiple ingested JUnit test runs.
+package analysis
+
+import (
+	"strings"
+
+	"github.com/joshdk/go-junit"
+)
+
+// Key is a stable identifier for a test, which can be used to correlate that
+// test across multiple runs.
+type Key struct {
+	// Suite is the path of suites that the test is nested within, ordered
+	// from outermost to innermost, and separated by slashes.
+	Suite string `json:"suite" yaml:"suite"`
+
+	// Classname is the classname of the test.
+	Classname string `json:"classname" yaml:"classname"`
+
+	// Name is the name of the test.
+	Name string `json:"name" yaml:"name"`
+}
+
+// KeyOf returns the key for the given test, which is nested within the given
+// chain of suites.
+func KeyOf(parents []junit.Suite, test junit.Test) Key {
+	names := make([]string, len(parents))
+	for index, parent := range parents {
+		names[index] = parent.Name
+		if names[index] == "" {
+			names[index] = parent.Package
+		}
+	}
+
+	return Key{
+		Suite:     strings.Join(names, "/"),
+		Classname: test.Classname,
+		Name:      test.Name,
+	}
+}
+
+// String returns a textual form of the key.
+func (k Key) String() string {
+	parts := make([]string, 0, 3)
+
+	for _, part := range []string{k.Suite, k.Classname, k.Name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, " > ")
+}
+
+// index returns every test in the given suites by key, along with the order in
+// which each key was first encountered. If multiple tests share a key, the
+// last one wins.
+func index(suites []junit.Suite) (map[Key]junit.Test, []Key) {
+	var (
+		tests = make(map[Key]junit.Test)
+		order []Key
+	)
+
+	junit.Walk(suites, func(parents []junit.Suite, test junit.Test) {
+		key := KeyOf(parents, test)
+		if _, found := tests[key]; !found {
+			order = append(order, key)
+		}
+		tests[key] = test
+	})
+
+	return tests, order
+}
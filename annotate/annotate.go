@@ -0,0 +1,237 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+// Package annotate exposes several library functions for reporting failed
+// and erroneous tests as annotations in CI systems, such as GitHub Actions,
+// GitLab, and Azure Pipelines.
+package annotate
+
+import (
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/joshdk/go-junit"
+	"github.com/joshdk/go-junit/analysis"
+)
+
+// Annotation describes a single failed or erroneous test, along with the
+// location in source that it should be reported against.
+type Annotation struct {
+	// Key identifies the test.
+	Key analysis.Key
+
+	// Status is the status of the test, which is either failed or error.
+	Status junit.Status
+
+	// Message is a short description of the failure.
+	Message string
+
+	// Details is the error body of the test, excluding any part of it that
+	// is already included in the message.
+	Details string
+
+	// File is the path of the source file that the failure occurred in, or
+	// empty if it is unknown.
+	File string
+
+	// Line is the line number that the failure occurred on, or zero if it is
+	// unknown.
+	Line int
+}
+
+// Option configures the behavior of annotations.
+type Option func(*options)
+
+type options struct {
+	root string
+}
+
+// WithRoot configures the directory that file paths are made relative to.
+// CI systems usually expect paths relative to the root of the repository,
+// whereas test runners often report absolute paths. Paths that are not within
+// the root are left as they are.
+func WithRoot(root string) Option {
+	return func(config *options) {
+		config.root = root
+	}
+}
+
+// Annotations returns an annotation for every failed or erroneous test in the
+// given suites, in the order that they are encountered.
+func Annotations(suites []junit.Suite, opts ...Option) []Annotation {
+	var config options
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	var annotations []Annotation
+
+	junit.Walk(suites, func(parents []junit.Suite, test junit.Test) {
+		if test.Status != junit.StatusFailed && test.Status != junit.StatusError {
+			return
+		}
+
+		annotation := Annotation{
+			Key:    analysis.KeyOf(parents, test),
+			Status: test.Status,
+		}
+
+		details := test.Details()
+		body := details.Body
+
+		if annotation.Message = strings.TrimSpace(test.Message); annotation.Message == "" {
+			annotation.Message = strings.TrimSpace(details.Message)
+		}
+
+		// Otherwise, the first line of the error body often describes it, and
+		// only the remainder of the body is needed.
+		if annotation.Message == "" {
+			lines := strings.SplitN(strings.TrimSpace(body), "\n", 2)
+			annotation.Message, body = strings.TrimSpace(lines[0]), ""
+
+			if len(lines) > 1 {
+				body = lines[1]
+			}
+		}
+
+		if body = strings.TrimSpace(dedent(body)); body != annotation.Message {
+			annotation.Details = body
+		}
+
+		annotation.File, annotation.Line = Locate(test)
+		annotation.File = config.relative(annotation.File)
+
+		annotations = append(annotations, annotation)
+	})
+
+	return annotations
+}
+
+// relative returns the given path relative to the configured root, if it is
+// within that root.
+func (config options) relative(file string) string {
+	if config.root == "" || file == "" || filepath.IsAbs(file) != filepath.IsAbs(config.root) {
+		return file
+	}
+
+	rel, err := filepath.Rel(config.root, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return file
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// dedent removes the indentation that is common to every non-blank line of
+// the given text, which is often left over from the layout of an XML report.
+func dedent(text string) string {
+	lines := strings.Split(text, "\n")
+	common := -1
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if indent := len(line) - len(strings.TrimLeft(line, " \t")); common < 0 || indent < common {
+			common = indent
+		}
+	}
+
+	for index, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines[index] = ""
+		} else {
+			lines[index] = line[common:]
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Locate returns the source file and line number of the given test, or an
+// empty file and zero line if they are unknown.
+//
+// The file and line properties of the test are used if present, as reported
+// by PHPUnit for example. Otherwise, they are taken from the frames of the
+// stack trace of the test error, as parsed by junit.Error.StackTrace. A frame
+// is preferred over the line property if they refer to the same file, since
+// the frame points to the failed assertion rather than to the start of the
+// test.
+func Locate(test junit.Test) (string, int) {
+	file := test.Properties["file"]
+	line, _ := strconv.Atoi(test.Properties["line"])
+
+	candidates := locations(test.Details())
+
+	if file != "" {
+		for _, candidate := range candidates {
+			if samePath(candidate.File, file) {
+				return file, candidate.Line
+			}
+		}
+
+		return file, line
+	}
+
+	// Stack traces often begin in the frames of an assertion library, so
+	// prefer a file that is named after the class of the test.
+	if class := className(test.Classname); class != "" {
+		for _, candidate := range candidates {
+			base := path.Base(filepath.ToSlash(candidate.File))
+			if strings.TrimSuffix(base, path.Ext(base)) == class {
+				return candidate.File, candidate.Line
+			}
+		}
+	}
+
+	if len(candidates) > 0 {
+		return candidates[0].File, candidates[0].Line
+	}
+
+	return "", 0
+}
+
+// locations returns the frames of the stack trace of the given error that
+// have a known file and line number, in order of relevance.
+func locations(details junit.Error) []junit.Frame {
+	var found []junit.Frame
+
+	for _, exception := range details.StackTrace() {
+		for _, frame := range exception.Frames {
+			if frame.File != "" && frame.Line > 0 {
+				found = append(found, frame)
+			}
+		}
+	}
+
+	return found
+}
+
+// samePath reports if the given paths refer to the same file, allowing for
+// either path to be a suffix of the other.
+func samePath(a, b string) bool {
+	a, b = filepath.ToSlash(a), filepath.ToSlash(b)
+
+	return a == b || strings.HasSuffix(a, "/"+b) || strings.HasSuffix(b, "/"+a)
+}
+
+// className returns the unqualified name of the given classname, without any
+// package or nested class names, such as ExampleTest for
+// com.example.ExampleTest$Nested.
+func className(classname string) string {
+	if index := strings.IndexByte(classname, '$'); index >= 0 {
+		classname = classname[:index]
+	}
+
+	if index := strings.LastIndexAny(classname, `./\:`); index >= 0 {
+		classname = classname[index+1:]
+	}
+
+	return classname
+}
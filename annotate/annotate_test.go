@@ -0,0 +1,197 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package annotate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/joshdk/go-junit"
+	"github.com/joshdk/go-junit/analysis"
+)
+
+// example returns suites with a test of every status, including failures
+// that can and cannot be located.
+func example() []junit.Suite {
+	return []junit.Suite{
+		{
+			Name: "tests",
+			Tests: []junit.Test{
+				{
+					Name:      "passes",
+					Classname: "SampleTest",
+					Status:    junit.StatusPassed,
+				},
+				{
+					Name:       "fails",
+					Classname:  "SampleTest",
+					Status:     junit.StatusFailed,
+					Properties: map[string]string{"file": "/src/tests/SampleTest.php", "line": "7"},
+					Error: junit.Error{
+						Body: "SampleTest::fails\n    Failed asserting that false is true.\n\n    /src/tests/SampleTest.php:9\n",
+					},
+				},
+				{
+					Name:      "errors",
+					Classname: "example",
+					Status:    junit.StatusError,
+					Message:   "panic: 100%, really",
+					Error:     errors.New("goroutine 1 [running]:\nexample.TestErrors(0xc000007860)\n\t/src/example_test.go:12 +0x1d"),
+				},
+				{
+					Name:   "skips",
+					Status: junit.StatusSkipped,
+				},
+				{
+					Name:   "unknown",
+					Status: junit.StatusFailed,
+					Error:  junit.Error{Message: "expected true"},
+				},
+			},
+		},
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	expected := []Annotation{
+		{
+			Key:     analysis.Key{Suite: "tests", Classname: "SampleTest", Name: "fails"},
+			Status:  junit.StatusFailed,
+			Message: "SampleTest::fails",
+			Details: "Failed asserting that false is true.\n\n/src/tests/SampleTest.php:9",
+			File:    "tests/SampleTest.php",
+			Line:    9,
+		},
+		{
+			Key:     analysis.Key{Suite: "tests", Classname: "example", Name: "errors"},
+			Status:  junit.StatusError,
+			Message: "panic: 100%, really",
+			Details: "goroutine 1 [running]:\nexample.TestErrors(0xc000007860)\n\t/src/example_test.go:12 +0x1d",
+			File:    "example_test.go",
+			Line:    12,
+		},
+		{
+			Key:     analysis.Key{Suite: "tests", Name: "unknown"},
+			Status:  junit.StatusFailed,
+			Message: "expected true",
+		},
+	}
+
+	assertEqual(t, expected, Annotations(example(), WithRoot("/src")))
+}
+
+func TestAnnotationsFile(t *testing.T) {
+	suites, err := junit.IngestFile("../testdata/phpunit.xml")
+	assertNoError(t, err)
+
+	annotations := Annotations(suites, WithRoot("/untitled"))
+	assertEqual(t, 3, len(annotations))
+
+	actual := annotations[0]
+	assertEqual(t, `SampleTest::testB with data set "bool" (false)`, actual.Message)
+	assertEqual(t, "should be true\nFailed asserting that false matches expected true.\n\n/untitled/tests/SampleTest.php:18", actual.Details)
+	assertEqual(t, "tests/SampleTest.php", actual.File)
+	assertEqual(t, 18, actual.Line)
+}
+
+func TestLocate(t *testing.T) {
+	tests := []struct {
+		title string
+		test  junit.Test
+		file  string
+		line  int
+	}{
+		{
+			title: "none",
+		},
+		{
+			title: "properties",
+			test: junit.Test{
+				Properties: map[string]string{"file": "tests/SampleTest.php", "line": "7"},
+			},
+			file: "tests/SampleTest.php",
+			line: 7,
+		},
+		{
+			title: "properties with body",
+			test: junit.Test{
+				Properties: map[string]string{"file": "/src/tests/SampleTest.php", "line": "7"},
+				Error:      junit.Error{Body: "/src/vendor/Assert.php:3\n/src/tests/SampleTest.php:9"},
+			},
+			file: "/src/tests/SampleTest.php",
+			line: 9,
+		},
+		{
+			title: "error pointer",
+			test: junit.Test{
+				Error: &junit.Error{Body: "example_test.go:12: expected true"},
+			},
+			file: "example_test.go",
+			line: 12,
+		},
+		{
+			title: "java",
+			test: junit.Test{
+				Classname: "com.example.ExampleTest$Nested",
+				Error: junit.Error{Body: "java.lang.AssertionError: expected true\n" +
+					"\tat org.junit.Assert.fail(Assert.java:88)\n" +
+					"\tat com.example.ExampleTest$Nested.run(ExampleTest.java:42)\n"},
+			},
+			file: "ExampleTest.java",
+			line: 42,
+		},
+		{
+			title: "python",
+			test: junit.Test{
+				Classname: "tests.test_example",
+				Error: junit.Error{Body: "Traceback (most recent call last):\n" +
+					"  File \"/src/tests/helpers.py\", line 3, in check\n" +
+					"  File \"/src/lib/example.py\", line 21, in run\n" +
+					"AssertionError"},
+			},
+			file: "/src/lib/example.py",
+			line: 21,
+		},
+		{
+			title: "dotnet",
+			test: junit.Test{
+				Error: junit.Error{Body: "at Example.Tests.Run() in C:\\src\\Example Tests\\Tests.cs:line 27"},
+			},
+			file: "C:\\src\\Example Tests\\Tests.cs",
+			line: 27,
+		},
+		{
+			title: "javascript",
+			test: junit.Test{
+				Error: junit.Error{Message: "expected 1 to equal 2", Body: "at Context.<anonymous> (test/example.spec.js:10:5)"},
+			},
+			file: "test/example.spec.js",
+			line: 10,
+		},
+		{
+			title: "go",
+			test: junit.Test{
+				Error: errors.New("    example_test.go:12: expected true"),
+			},
+			file: "example_test.go",
+			line: 12,
+		},
+		{
+			title: "not a source file",
+			test: junit.Test{
+				Error: errors.New("dial tcp example.com:8080: connection refused"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			file, line := Locate(test.test)
+			assertEqual(t, test.file, file)
+			assertEqual(t, test.line, line)
+		})
+	}
+}
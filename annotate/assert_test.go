@@ -0,0 +1,33 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package annotate
+
+import (
+	"reflect"
+	"testing"
+)
+
+// assertEqual is a testing helper function which asserts that the given
+// objects are equal.
+func assertEqual(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("objects were not equal: \n"+
+			"expected: %v\n"+
+			"actual  : %v", expected, actual)
+	}
+}
+
+// assertNoError is a testing helper function which asserts that the given
+// error is nil.
+func assertNoError(t *testing.T, actual error) {
+	t.Helper()
+	if actual != nil {
+		t.Fatalf("error was not nil: \n"+
+			"expected: no error\n"+
+			"actual  : %v", actual)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package annotate
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/joshdk/go-junit"
+)
+
+// Azure writes an annotation for every failed or erroneous test in the given
+// suites to the given writer, as Azure Pipelines logging commands such as:
+//
+//	##vso[task.logissue type=error;sourcepath=tests/SampleTest.php;linenumber=18;]SampleTest > testB: message
+//
+// These commands are interpreted when printed by a task of a pipeline, and
+// shown as errors in the summary of the pipeline run.
+func Azure(w io.Writer, suites []junit.Suite, opts ...Option) error {
+	writer := bufio.NewWriter(w)
+
+	for _, annotation := range Annotations(suites, opts...) {
+		params := "type=error;"
+
+		if annotation.File != "" {
+			params += "sourcepath=" + azureProperty(annotation.File) + ";"
+
+			if annotation.Line > 0 {
+				params += "linenumber=" + strconv.Itoa(annotation.Line) + ";"
+			}
+		}
+
+		message := annotation.Key.String()
+		if annotation.Message != "" {
+			message += ": " + annotation.Message
+		}
+
+		fmt.Fprintf(writer, "##vso[task.logissue %s]%s\n", params, azureData(message))
+	}
+
+	return writer.Flush()
+}
+
+// azureData escapes the given text for use as the message of a logging
+// command.
+var azureData = strings.NewReplacer( //nolint:gochecknoglobals
+	"%", "%AZP25",
+	"\r", "%0D",
+	"\n", "%0A",
+).Replace
+
+// azureProperty escapes the given text for use as the value of a property of
+// a logging command.
+var azureProperty = strings.NewReplacer( //nolint:gochecknoglobals
+	"%", "%AZP25",
+	"\r", "%0D",
+	"\n", "%0A",
+	"]", "%5D",
+	";", "%3B",
+).Replace
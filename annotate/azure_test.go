@@ -0,0 +1,26 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package annotate
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAzure(t *testing.T) {
+	var buf bytes.Buffer
+	assertNoError(t, Azure(&buf, example(), WithRoot("/src")))
+
+	expected := "##vso[task.logissue type=error;sourcepath=tests/SampleTest.php;linenumber=9;]tests > SampleTest > fails: SampleTest::fails\n" +
+		"##vso[task.logissue type=error;sourcepath=example_test.go;linenumber=12;]tests > example > errors: panic: 100%AZP25, really\n" +
+		"##vso[task.logissue type=error;]tests > unknown: expected true\n"
+
+	assertEqual(t, expected, buf.String())
+}
+
+func TestAzureProperty(t *testing.T) {
+	assertEqual(t, "a%3Bb%5Dc%AZP25d%0Ae", azureProperty("a;b]c%d\ne"))
+}
@@ -0,0 +1,69 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package annotate
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/joshdk/go-junit"
+)
+
+// GitHub writes an annotation for every failed or erroneous test in the given
+// suites to the given writer, as GitHub Actions workflow commands such as:
+//
+//	::error file=tests/SampleTest.php,line=18,title=SampleTest > testB::message
+//
+// These commands are interpreted when printed by a step of a workflow, and
+// shown alongside the referenced lines in the summary of the workflow run and
+// in pull requests.
+func GitHub(w io.Writer, suites []junit.Suite, opts ...Option) error {
+	writer := bufio.NewWriter(w)
+
+	for _, annotation := range Annotations(suites, opts...) {
+		var params []string
+
+		if annotation.File != "" {
+			params = append(params, "file="+githubProperty(annotation.File))
+
+			if annotation.Line > 0 {
+				params = append(params, "line="+strconv.Itoa(annotation.Line))
+			}
+		}
+
+		params = append(params, "title="+githubProperty(annotation.Key.String()))
+
+		message := annotation.Message
+		if annotation.Details != "" {
+			message += "\n\n" + annotation.Details
+		}
+
+		fmt.Fprintf(writer, "::error %s::%s\n", strings.Join(params, ","), githubData(message))
+	}
+
+	return writer.Flush()
+}
+
+// githubData escapes the given text for use as the message of a workflow
+// command.
+var githubData = strings.NewReplacer( //nolint:gochecknoglobals
+	"%", "%25",
+	"\r", "%0D",
+	"\n", "%0A",
+).Replace
+
+// githubProperty escapes the given text for use as the value of a parameter
+// of a workflow command.
+var githubProperty = strings.NewReplacer( //nolint:gochecknoglobals
+	"%", "%25",
+	"\r", "%0D",
+	"\n", "%0A",
+	":", "%3A",
+	",", "%2C",
+).Replace
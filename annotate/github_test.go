@@ -0,0 +1,26 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package annotate
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGitHub(t *testing.T) {
+	var buf bytes.Buffer
+	assertNoError(t, GitHub(&buf, example(), WithRoot("/src")))
+
+	expected := "::error file=tests/SampleTest.php,line=9,title=tests > SampleTest > fails::SampleTest::fails%0A%0AFailed asserting that false is true.%0A%0A/src/tests/SampleTest.php:9\n" +
+		"::error file=example_test.go,line=12,title=tests > example > errors::panic: 100%25, really%0A%0Agoroutine 1 [running]:%0Aexample.TestErrors(0xc000007860)%0A\t/src/example_test.go:12 +0x1d\n" +
+		"::error title=tests > unknown::expected true\n"
+
+	assertEqual(t, expected, buf.String())
+}
+
+func TestGitHubProperty(t *testing.T) {
+	assertEqual(t, "a%3Ab%2Cc%25d%0Ae", githubProperty("a:b,c%d\ne"))
+}
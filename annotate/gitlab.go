@@ -0,0 +1,87 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package annotate
+
+import (
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"encoding/json"
+	"io"
+
+	"github.com/joshdk/go-junit"
+)
+
+// gitlabIssue is a single issue within a GitLab Code Quality report.
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabLines struct {
+	Begin int `json:"begin"`
+}
+
+// GitLab writes an annotation for every failed or erroneous test in the given
+// suites to the given writer, as a GitLab Code Quality report.
+//
+// The report can be uploaded as a codequality artifact of a job, and is shown
+// alongside the referenced lines in merge requests. Failed tests are reported
+// with a major severity, and erroneous tests with a critical severity. Since
+// every issue in the report must reference a file, tests whose location is
+// unknown are omitted. The fingerprint of each issue is derived from the name
+// of its test, so that the same failure is tracked across pipelines.
+func GitLab(w io.Writer, suites []junit.Suite, opts ...Option) error {
+	issues := []gitlabIssue{}
+
+	for _, annotation := range Annotations(suites, opts...) {
+		if annotation.File == "" {
+			continue
+		}
+
+		name := annotation.Key.String()
+		sum := md5.Sum([]byte(name)) //nolint:gosec
+
+		issue := gitlabIssue{
+			Description: name,
+			CheckName:   "junit-" + string(annotation.Status),
+			Fingerprint: hex.EncodeToString(sum[:]),
+			Severity:    "major",
+			Location: gitlabLocation{
+				Path:  annotation.File,
+				Lines: gitlabLines{Begin: annotation.Line},
+			},
+		}
+
+		if annotation.Message != "" {
+			issue.Description += ": " + annotation.Message
+		}
+
+		if annotation.Status == junit.StatusError {
+			issue.Severity = "critical"
+		}
+
+		// GitLab requires a line number, so report the start of the file.
+		if issue.Location.Lines.Begin == 0 {
+			issue.Location.Lines.Begin = 1
+		}
+
+		issues = append(issues, issue)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(issues)
+}
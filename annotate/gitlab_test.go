@@ -0,0 +1,54 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package annotate
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/joshdk/go-junit"
+)
+
+func TestGitLab(t *testing.T) {
+	var buf bytes.Buffer
+	assertNoError(t, GitLab(&buf, example(), WithRoot("/src")))
+
+	expected := `[
+  {
+    "description": "tests > SampleTest > fails: SampleTest::fails",
+    "check_name": "junit-failed",
+    "fingerprint": "984d6204f97e8b7b98398cc7ea4d2668",
+    "severity": "major",
+    "location": {
+      "path": "tests/SampleTest.php",
+      "lines": {
+        "begin": 9
+      }
+    }
+  },
+  {
+    "description": "tests > example > errors: panic: 100%, really",
+    "check_name": "junit-error",
+    "fingerprint": "58f32ddf3964adb70750932a10931f48",
+    "severity": "critical",
+    "location": {
+      "path": "example_test.go",
+      "lines": {
+        "begin": 12
+      }
+    }
+  }
+]
+`
+
+	assertEqual(t, expected, buf.String())
+}
+
+func TestGitLabEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	assertNoError(t, GitLab(&buf, []junit.Suite{}))
+	assertEqual(t, "[]\n", buf.String())
+}
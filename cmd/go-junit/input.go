@@ -0,0 +1,129 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/joshdk/go-junit"
+)
+
+// ingest reads every report from the given paths, and returns all contained
+// test suite definitions. Each path may be a file, a directory, or a glob
+// pattern, and "-" reads from the given stdin. If no paths are given, stdin is
+// read.
+func ingest(paths []string, stdin io.Reader) ([]junit.Suite, error) {
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	suites := make([]junit.Suite, 0)
+
+	for _, path := range paths {
+		found, err := ingestPath(path, stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		suites = append(suites, found...)
+	}
+
+	return suites, nil
+}
+
+// ingestPath reads every report from the given path.
+func ingestPath(path string, stdin io.Reader) ([]junit.Suite, error) {
+	if path == "-" {
+		return ingestStdin(stdin)
+	}
+
+	info, err := os.Stat(path)
+
+	switch {
+	case err == nil && info.IsDir():
+		return ingestDir(path)
+
+	case err == nil:
+		return ingestFile(path)
+
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	// The path does not exist, so may be a glob pattern instead.
+	matches, err := filepath.Glob(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%s: no such file or directory", path)
+	}
+
+	var suites []junit.Suite
+
+	for _, match := range matches {
+		found, err := ingestPath(match, stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		suites = append(suites, found...)
+	}
+
+	return suites, nil
+}
+
+// ingestStdin reads a single report from the given stdin.
+func ingestStdin(stdin io.Reader) ([]junit.Suite, error) {
+	data, err := ioutil.ReadAll(stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	format := junit.DetectFormat(data)
+	if format == junit.FormatUnknown {
+		return nil, errors.New("stdin: unrecognized report format")
+	}
+
+	return junit.IngestFormat(bytes.NewReader(data), format)
+}
+
+// ingestDir reads every report in the given directory, skipping files whose
+// format is not recognized.
+func ingestDir(directory string) ([]junit.Suite, error) {
+	reports, err := junit.IngestDirAuto(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var suites []junit.Suite
+	for _, report := range reports {
+		suites = append(suites, report.Suites...)
+	}
+
+	return suites, nil
+}
+
+// ingestFile reads the report in the given file.
+func ingestFile(filename string) ([]junit.Suite, error) {
+	report, err := junit.IngestFileAuto(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if report.Format == junit.FormatUnknown {
+		return nil, fmt.Errorf("%s: unrecognized report format", filename)
+	}
+
+	return report.Suites, nil
+}
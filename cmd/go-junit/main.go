@@ -0,0 +1,368 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+// Command go-junit inspects test reports in any format supported by the junit
+// package. Reports are read from the files, directories, and glob patterns
+// given as arguments, or from stdin if none are given.
+//
+// The exit status is 0 if no tests failed or errored, 1 if any did, and 2 if
+// the arguments were invalid or the reports could not be read. Commands that
+// convert or render reports ignore the results of the tests.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/joshdk/go-junit"
+	"github.com/joshdk/go-junit/analysis"
+	"github.com/joshdk/go-junit/annotate"
+	"github.com/joshdk/go-junit/render"
+)
+
+const (
+	// exitPassed is the exit status when no tests failed or errored.
+	exitPassed = 0
+
+	// exitFailed is the exit status when any tests failed or errored.
+	exitFailed = 1
+
+	// exitError is the exit status when the arguments were invalid, or the
+	// reports could not be read.
+	exitError = 2
+)
+
+const usage = `Usage: go-junit <command> [flags] [path...]
+
+Commands:
+  summary    Print the total number of tests with each status.
+  list       Print every test, optionally filtered by status.
+  tree       Print every suite and test as a tree.
+  failures   Print every failed or erroneous test, along with its error.
+  convert    Convert reports to JUnit XML, JSON, or YAML.
+  html       Render reports as a self-contained HTML page.
+  markdown   Render reports as a Markdown summary.
+  annotate   Print failures as GitHub, GitLab, or Azure CI annotations.
+
+Paths may be files, directories, or glob patterns. Reports are read from stdin
+if no paths are given, or if a path is "-". The format of each report is
+detected automatically.
+
+The exit status is 0 if no tests failed or errored, 1 if any did, and 2 if the
+arguments were invalid or the reports could not be read. The convert, html,
+markdown, and annotate commands only exit with a non-zero status if the reports
+could not be read or written.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command given by the given arguments, and returns its exit
+// status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+
+		return exitError
+	}
+
+	command, args := args[0], args[1:]
+
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fmt.Fprintf(stderr, "\nFlags for %s:\n", command)
+		flags.PrintDefaults()
+	}
+
+	var (
+		print func(suites []junit.Suite) error
+		gate  = true
+	)
+
+	switch command {
+	case "summary":
+		print = func(suites []junit.Suite) error {
+			printSummary(stdout, suites)
+
+			return nil
+		}
+
+	case "list":
+		var statuses statusList
+		flags.Var(&statuses, "status", "comma separated `statuses` to list, such as failed,error")
+		print = func(suites []junit.Suite) error {
+			printList(stdout, suites, statuses)
+
+			return nil
+		}
+
+	case "tree":
+		compact := flags.Bool("compact", false, "only print failed or erroneous tests")
+		color := flags.String("color", "auto", "whether to colour output, one of `auto`, always, or never")
+		print = func(suites []junit.Suite) error {
+			opts := []render.TerminalOption{}
+
+			switch *color {
+			case "auto":
+			case "always":
+				opts = append(opts, render.WithColor(true))
+			case "never":
+				opts = append(opts, render.WithColor(false))
+			default:
+				return fmt.Errorf("unknown color mode %q", *color)
+			}
+
+			if *compact {
+				opts = append(opts, render.WithCompact())
+			}
+
+			return render.Terminal(stdout, suites, opts...)
+		}
+
+	case "failures":
+		output := flags.Bool("output", false, "also print the output of each test")
+		print = func(suites []junit.Suite) error {
+			printFailures(stdout, suites, *output)
+
+			return nil
+		}
+
+	case "convert":
+		to := flags.String("to", "json", "output `format`, one of xml, json, or yaml")
+		gate = false
+		print = func(suites []junit.Suite) error {
+			return convert(stdout, suites, *to)
+		}
+
+	case "html":
+		title := flags.String("title", "Test Report", "page `title`")
+		gate = false
+		print = func(suites []junit.Suite) error {
+			return render.HTML(stdout, suites, render.WithTitle(*title))
+		}
+
+	case "markdown":
+		heading := flags.String("heading", "Test Results", "summary `heading`")
+		budget := flags.Int("budget", 65536, "maximum summary size in `bytes`, or 0 for unlimited")
+		gate = false
+		print = func(suites []junit.Suite) error {
+			return render.Markdown(stdout, suites, render.WithHeading(*heading), render.WithBudget(*budget))
+		}
+
+	case "annotate":
+		format := flags.String("format", "github", "annotation `format`, one of github, gitlab, or azure")
+		root := flags.String("root", "", "`directory` that file paths are made relative to")
+		gate = false
+		print = func(suites []junit.Suite) error {
+			return annotations(stdout, suites, *format, annotate.WithRoot(*root))
+		}
+
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+
+		return exitPassed
+
+	default:
+		fmt.Fprintf(stderr, "go-junit: unknown command %q\n\n%s", command, usage)
+
+		return exitError
+	}
+
+	// Asking for help is not an error, even though the flags were not parsed.
+	switch err := flags.Parse(args); {
+	case err == flag.ErrHelp:
+		return exitPassed
+	case err != nil:
+		return exitError
+	}
+
+	suites, err := ingest(flags.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "go-junit: %v\n", err)
+
+		return exitError
+	}
+
+	if err := print(suites); err != nil {
+		fmt.Fprintf(stderr, "go-junit: %v\n", err)
+
+		return exitError
+	}
+
+	if totals := junit.Sum(suites); gate && totals.Failed+totals.Error > 0 {
+		return exitFailed
+	}
+
+	return exitPassed
+}
+
+// printSummary prints the total number of tests with each status.
+func printSummary(w io.Writer, suites []junit.Suite) {
+	totals := junit.Sum(suites)
+
+	fmt.Fprintf(w, "Tests:   %d\n", totals.Tests)
+	fmt.Fprintf(w, "Passed:  %d\n", totals.Passed)
+	fmt.Fprintf(w, "Skipped: %d\n", totals.Skipped)
+	fmt.Fprintf(w, "Failed:  %d\n", totals.Failed)
+	fmt.Fprintf(w, "Error:   %d\n", totals.Error)
+	fmt.Fprintf(w, "Time:    %v\n", totals.Duration)
+}
+
+// printList prints every test with one of the given statuses, or every test
+// if no statuses are given.
+func printList(w io.Writer, suites []junit.Suite, statuses []junit.Status) {
+	junit.Walk(suites, func(parents []junit.Suite, test junit.Test) {
+		if len(statuses) > 0 && !hasStatus(statuses, test.Status) {
+			return
+		}
+
+		fmt.Fprintf(w, "%-7s  %s (%v)\n", test.Status, analysis.KeyOf(parents, test), test.Duration)
+	})
+}
+
+// printFailures prints every failed or erroneous test, along with its error,
+// and optionally its output.
+func printFailures(w io.Writer, suites []junit.Suite, output bool) {
+	junit.Walk(suites, func(parents []junit.Suite, test junit.Test) {
+		if test.Status != junit.StatusFailed && test.Status != junit.StatusError {
+			return
+		}
+
+		fmt.Fprintf(w, "--- %s: %s (%v)\n", strings.ToUpper(string(test.Status)), analysis.KeyOf(parents, test), test.Duration)
+
+		if test.Message != "" {
+			fmt.Fprintln(w, indent(test.Message))
+		}
+
+		if test.Error != nil && test.Error.Error() != test.Message {
+			fmt.Fprintln(w, indent(test.Error.Error()))
+		}
+
+		if output && test.SystemOut != "" {
+			fmt.Fprintln(w, "    stdout:")
+			fmt.Fprintln(w, indent(indent(test.SystemOut)))
+		}
+
+		if output && test.SystemErr != "" {
+			fmt.Fprintln(w, "    stderr:")
+			fmt.Fprintln(w, indent(indent(test.SystemErr)))
+		}
+	})
+}
+
+// convert writes the given suites in the given format.
+func convert(w io.Writer, suites []junit.Suite, format string) error {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch format {
+	case "xml", "junit":
+		data, err = junit.Marshal(suites)
+	case "json":
+		if data, err = junit.MarshalJSON(suites); err == nil {
+			data = append(data, '\n')
+		}
+	case "yaml", "yml":
+		data, err = junit.MarshalYAML(suites)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+
+	return err
+}
+
+// annotations writes an annotation for every failed or erroneous test in the
+// given suites, in the given format.
+func annotations(w io.Writer, suites []junit.Suite, format string, opts ...annotate.Option) error {
+	switch format {
+	case "github":
+		return annotate.GitHub(w, suites, opts...)
+	case "gitlab":
+		return annotate.GitLab(w, suites, opts...)
+	case "azure":
+		return annotate.Azure(w, suites, opts...)
+	default:
+		return fmt.Errorf("unknown annotation format %q", format)
+	}
+}
+
+// statusList is a flag of comma separated statuses, which rejects any unknown
+// status as invalid usage.
+type statusList []junit.Status
+
+func (list *statusList) String() string {
+	names := make([]string, 0, len(*list))
+	for _, status := range *list {
+		names = append(names, string(status))
+	}
+
+	return strings.Join(names, ",")
+}
+
+func (list *statusList) Set(value string) error {
+	statuses, err := parseStatuses(value)
+	if err != nil {
+		return err
+	}
+
+	*list = statuses
+
+	return nil
+}
+
+// parseStatuses parses the given comma separated list of statuses. The
+// status "failure" is accepted as an alias of "failed".
+func parseStatuses(value string) ([]junit.Status, error) {
+	var statuses []junit.Status
+
+	for _, name := range strings.Split(value, ",") {
+		switch name = strings.ToLower(strings.TrimSpace(name)); name {
+		case "":
+			continue
+		case "failure":
+			statuses = append(statuses, junit.StatusFailed)
+		case string(junit.StatusPassed), string(junit.StatusSkipped), string(junit.StatusFailed), string(junit.StatusError):
+			statuses = append(statuses, junit.Status(name))
+		default:
+			return nil, fmt.Errorf("unknown status %q", name)
+		}
+	}
+
+	return statuses, nil
+}
+
+func hasStatus(statuses []junit.Status, status junit.Status) bool {
+	for _, candidate := range statuses {
+		if candidate == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// indent indents every line of the given text by four spaces.
+func indent(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for index, line := range lines {
+		lines[index] = "    " + line
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,215 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/joshdk/go-junit"
+)
+
+const passingReport = `<testsuite name="suite">
+  <testcase name="first" classname="pkg" time="1.5"/>
+  <testcase name="second" classname="pkg" time="0.5"><skipped/></testcase>
+</testsuite>`
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		title    string
+		args     []string
+		stdin    string
+		code     int
+		stdout   string
+		contains string
+	}{
+		{
+			title:  "summary stdin",
+			args:   []string{"summary"},
+			stdin:  passingReport,
+			code:   exitPassed,
+			stdout: "Tests:   2\nPassed:  1\nSkipped: 1\nFailed:  0\nError:   0\nTime:    2s\n",
+		},
+		{
+			title:  "list stdin with status filter",
+			args:   []string{"list", "-status", "skipped", "-"},
+			stdin:  passingReport,
+			code:   exitPassed,
+			stdout: "skipped  suite > pkg > second (500ms)\n",
+		},
+		{
+			title: "list with unknown status",
+			args:  []string{"list", "-status", "failed,broken", "-"},
+			stdin: passingReport,
+			code:  exitError,
+		},
+		{
+			title:    "list file",
+			args:     []string{"list", "../../testdata/tap.tap"},
+			code:     exitFailed,
+			contains: "failed   First line of the input valid (12.5ms)\n",
+		},
+		{
+			title:    "failures with bodies",
+			args:     []string{"failures", "../../testdata/tap.tap"},
+			code:     exitFailed,
+			contains: "--- FAILED: First line of the input valid (12.5ms)\n    First line invalid\n    message: 'First line invalid'\n",
+		},
+		{
+			title:    "summary glob",
+			args:     []string{"summary", "../../testdata/*.trx"},
+			code:     exitFailed,
+			contains: "Tests:   5\n",
+		},
+		{
+			title:    "summary directory",
+			args:     []string{"summary", "../../testdata"},
+			code:     exitFailed,
+			contains: "Tests:",
+		},
+		{
+			title:    "convert to json",
+			args:     []string{"convert", "-to", "json", "../../testdata/tap.tap"},
+			code:     exitPassed,
+			contains: "\"status\": \"failed\",\n",
+		},
+		{
+			title:    "convert json to xml",
+			args:     []string{"convert", "-to", "xml"},
+			stdin:    `[{"name": "suite", "declared": {}, "tests": [{"name": "first", "status": "failed", "duration": "1.5s", "error": {"message": "broken"}}]}]`,
+			code:     exitPassed,
+			contains: "<testcase name=\"first\" time=\"1.500\">\n\t\t\t<failure message=\"broken\"></failure>",
+		},
+		{
+			title:    "convert to yaml",
+			args:     []string{"convert", "-to", "yaml", "../../testdata/tap.tap"},
+			code:     exitPassed,
+			contains: "    status: failed\n",
+		},
+		{
+			title:    "convert yaml to xml",
+			args:     []string{"convert", "-to", "xml"},
+			stdin:    "- name: suite\n  declared: {}\n  tests:\n  - name: first\n    status: failed\n    duration: 1.5s\n    error:\n      message: broken\n",
+			code:     exitPassed,
+			contains: "<testcase name=\"first\" time=\"1.500\">\n\t\t\t<failure message=\"broken\"></failure>",
+		},
+		{
+			title: "convert to unknown format",
+			args:  []string{"convert", "-to", "csv", "../../testdata/tap.tap"},
+			code:  exitError,
+		},
+		{
+			title:    "html",
+			args:     []string{"html", "-title", "Nightly", "../../testdata/tap.tap"},
+			code:     exitPassed,
+			contains: "<title>Nightly</title>",
+		},
+		{
+			title:    "markdown",
+			args:     []string{"markdown", "-budget", "300", "../../testdata/tap.tap"},
+			code:     exitPassed,
+			contains: "| 7 | 3 | 2 | 0 | 2 | 12.5ms |\n",
+		},
+		{
+			title:  "tree",
+			args:   []string{"tree", "-compact", "-color", "never", "-"},
+			stdin:  passingReport,
+			code:   exitPassed,
+			stdout: "\n2 tests: 1 passed, 0 failed, 0 errors, 1 skipped in 2s\n",
+		},
+		{
+			title:    "annotate",
+			args:     []string{"annotate", "-root", "/untitled", "../../testdata/phpunit.xml"},
+			code:     exitPassed,
+			contains: "::error file=tests/SampleTest.php,line=18,title=",
+		},
+		{
+			title:    "annotate for azure",
+			args:     []string{"annotate", "-format", "azure", "../../testdata/phpunit.xml"},
+			code:     exitPassed,
+			contains: "##vso[task.logissue type=error;sourcepath=/untitled/tests/SampleTest.php;linenumber=34;]",
+		},
+		{
+			title: "annotate in unknown format",
+			args:  []string{"annotate", "-format", "jenkins", "../../testdata/phpunit.xml"},
+			code:  exitError,
+		},
+		{
+			title: "unknown command",
+			args:  []string{"frobnicate"},
+			code:  exitError,
+		},
+		{
+			title: "no command",
+			code:  exitError,
+		},
+		{
+			title: "command help",
+			args:  []string{"summary", "-h"},
+			code:  exitPassed,
+		},
+		{
+			title: "unknown flag",
+			args:  []string{"summary", "-frobnicate"},
+			code:  exitError,
+		},
+		{
+			title: "missing file",
+			args:  []string{"summary", "../../testdata/missing.xml"},
+			code:  exitError,
+		},
+		{
+			title: "unrecognized stdin",
+			args:  []string{"summary"},
+			stdin: "hello world",
+			code:  exitError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(test.args, strings.NewReader(test.stdin), &stdout, &stderr)
+			assertEqual(t, test.code, code)
+
+			switch {
+			case test.stdout != "":
+				assertEqual(t, test.stdout, stdout.String())
+			case test.contains != "":
+				if !strings.Contains(stdout.String(), test.contains) {
+					t.Fatalf("output did not contain %q:\n%s", test.contains, stdout.String())
+				}
+			}
+
+			if code == exitError && stderr.Len() == 0 {
+				t.Fatal("expected an error to be printed")
+			}
+		})
+	}
+}
+
+func TestParseStatuses(t *testing.T) {
+	statuses, err := parseStatuses(" Failure, error,")
+	assertEqual(t, nil, err)
+	assertEqual(t, []junit.Status{junit.StatusFailed, junit.StatusError}, statuses)
+
+	_, err = parseStatuses("passed,broken")
+	assertEqual(t, `unknown status "broken"`, err.Error())
+}
+
+// assertEqual is a testing helper function which asserts that the given
+// objects are equal.
+func assertEqual(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("objects were not equal: \n"+
+			"expected: %v\n"+
+			"actual  : %v", expected, actual)
+	}
+}
@@ -0,0 +1,397 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ctrfSuiteSeparator separates the names of nested suites within the
+	// "suite" field of a CTRF test, as used by earlier versions of CTRF.
+	ctrfSuiteSeparator = " > "
+
+	// ctrfSpecVersion is the version of the CTRF specification that reports
+	// are written in.
+	ctrfSpecVersion = "1.0.0"
+)
+
+// IngestCTRF will parse the given CTRF (Common Test Report Format) JSON data
+// and return a slice of test suite definitions.
+//
+// Tests are grouped into suites by their "suite" field, which is split on
+// " > " into a chain of nested suites. Tests without a suite are grouped into
+// a suite named after the tool that ran them. The "status" field becomes the
+// test status, where pending tests are considered skipped, and tests with an
+// "other" status, or with a "rawStatus" of "error", are considered errors.
+// The "message" and "trace" fields become the message and body of the test
+// error, and the "filePath" and "line" fields become the "file" and "line"
+// test properties. Tests that were retried have an attempt recorded for each
+// failed execution, so that flaky tests remain flaky.
+func IngestCTRF(data []byte, opts ...Option) ([]Suite, error) {
+	return IngestCTRFReader(bytes.NewReader(data), opts...)
+}
+
+// IngestCTRFFile will parse the given CTRF JSON file and return a slice of
+// test suite definitions.
+func IngestCTRFFile(filename string, opts ...Option) ([]Suite, error) {
+	file, err := os.Open(filename) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint
+
+	return IngestCTRFReader(file, opts...)
+}
+
+// IngestCTRFReader will parse the given CTRF JSON reader and return a slice of
+// test suite definitions.
+func IngestCTRFReader(reader io.Reader, opts ...Option) ([]Suite, error) {
+	var report ctrfReport
+	if err := json.NewDecoder(reader).Decode(&report); err != nil {
+		return nil, err
+	}
+
+	var suites []Suite
+
+	for _, ctrf := range report.Results.Tests {
+		path := []string(ctrf.Suite)
+		if len(path) == 0 {
+			path = []string{report.Results.Tool.Name}
+		}
+
+		suite := ctrfSuite(&suites, path)
+		suite.Tests = append(suite.Tests, ingestCTRFTest(ctrf))
+	}
+
+	start := ctrfTime(report.Results.Summary.Start)
+	for index := range suites {
+		suites[index].Timestamp = start
+	}
+
+	aggregateAll(suites)
+
+	if suites == nil {
+		suites = []Suite{}
+	}
+
+	return newOptions(opts).finish(suites)
+}
+
+// ctrfSuite returns the suite at the given path of suite names, adding any
+// suites that do not already exist.
+func ctrfSuite(suites *[]Suite, path []string) *Suite {
+	for index := range *suites {
+		if (*suites)[index].Name == path[0] {
+			if len(path) == 1 {
+				return &(*suites)[index]
+			}
+
+			return ctrfSuite(&(*suites)[index].Suites, path[1:])
+		}
+	}
+
+	*suites = append(*suites, Suite{Name: path[0]})
+
+	return ctrfSuite(suites, path)
+}
+
+func ingestCTRFTest(ctrf ctrfTest) Test {
+	test := Test{
+		Name:      ctrf.Name,
+		Duration:  time.Duration(math.Round(ctrf.Duration * float64(time.Millisecond))),
+		Message:   ctrf.Message,
+		SystemOut: ctrfOutput(ctrf.Stdout),
+		SystemErr: ctrfOutput(ctrf.Stderr),
+	}
+
+	if ctrf.Extra != nil {
+		test.Classname = ctrf.Extra.Classname
+	}
+
+	switch {
+	case ctrf.RawStatus == "error" || ctrf.Status == "other":
+		test.Status = StatusError
+	case ctrf.Status == "failed":
+		test.Status = StatusFailed
+	case ctrf.Status == "skipped" || ctrf.Status == "pending":
+		test.Status = StatusSkipped
+	default:
+		test.Status = StatusPassed
+	}
+
+	if ctrf.FilePath != "" || ctrf.Line != 0 || len(ctrf.Tags) > 0 {
+		test.Properties = make(map[string]string)
+	}
+
+	if ctrf.FilePath != "" {
+		test.Properties["file"] = ctrf.FilePath
+	}
+
+	if ctrf.Line != 0 {
+		test.Properties["line"] = strconv.Itoa(ctrf.Line)
+	}
+
+	if len(ctrf.Tags) > 0 {
+		test.Properties["tags"] = strings.Join(ctrf.Tags, " ")
+	}
+
+	failures := 0
+
+	switch test.Status {
+	case StatusFailed, StatusError:
+		test.Error = Error{
+			Message: ctrf.Message,
+			Body:    ctrf.Trace,
+		}
+		failures = ctrf.Retries + 1
+	case StatusPassed:
+		if ctrf.Flaky {
+			failures = ctrf.Retries
+			if failures == 0 {
+				failures = 1
+			}
+		}
+	}
+
+	// Only the details of the final failure are known.
+	for index := 0; index < failures; index++ {
+		attempt := Attempt{
+			Status: StatusFailed,
+			Rerun:  index > 0,
+		}
+
+		if test.Error != nil && index == failures-1 {
+			attempt.Status = test.Status
+			attempt.Message = ctrf.Message
+			attempt.Body = ctrf.Trace
+			attempt.Duration = test.Duration
+		}
+
+		test.Attempts = append(test.Attempts, attempt)
+	}
+
+	return test
+}
+
+// MarshalCTRF returns the CTRF (Common Test Report Format) JSON encoding of the
+// given suites, attributed to the given tool name.
+//
+// Every test in the given suites, and in all of their nested suites, is
+// written with the names of the suites that it is nested within as its
+// "suite" field. Erroneous tests are written as failed, with a "rawStatus" of
+// "error", and tests with any other status that CTRF has no equivalent for
+// are written as "other". Tests that were rerun are written with the number
+// of reruns as their "retries" field, and are marked as flaky if they passed.
+// The "file" and "line" test properties become the "filePath" and "line"
+// fields. The report summary counts the written status of every test, and
+// starts at the earliest suite timestamp.
+func MarshalCTRF(suites []Suite, tool string) ([]byte, error) {
+	var (
+		report = ctrfReport{
+			ReportFormat: "CTRF",
+			SpecVersion:  ctrfSpecVersion,
+		}
+		totals = Sum(suites)
+		start  time.Time
+	)
+
+	report.Results.Tool.Name = tool
+	report.Results.Tests = make([]ctrfTest, 0)
+
+	for _, suite := range suites {
+		if !suite.Timestamp.IsZero() && (start.IsZero() || suite.Timestamp.Before(start)) {
+			start = suite.Timestamp
+		}
+	}
+
+	summary := &report.Results.Summary
+
+	Walk(suites, func(parents []Suite, test Test) {
+		ctrf := encodeCTRFTest(parents, test)
+		report.Results.Tests = append(report.Results.Tests, ctrf)
+		summary.Tests++
+
+		switch ctrf.Status {
+		case "passed":
+			summary.Passed++
+		case "failed":
+			summary.Failed++
+		case "pending":
+			summary.Pending++
+		case "skipped":
+			summary.Skipped++
+		default:
+			summary.Other++
+		}
+	})
+
+	if !start.IsZero() {
+		report.Results.Summary.Start = start.UnixNano() / int64(time.Millisecond)
+		report.Results.Summary.Stop = start.Add(totals.Duration).UnixNano() / int64(time.Millisecond)
+	}
+
+	return encodeJSON(report, "  ")
+}
+
+func encodeCTRFTest(parents []Suite, test Test) ctrfTest {
+	names := make([]string, len(parents))
+	for index, parent := range parents {
+		names[index] = parent.Name
+	}
+
+	ctrf := ctrfTest{
+		Name:     test.Name,
+		Duration: float64(test.Duration) / float64(time.Millisecond),
+		Message:  test.Message,
+		Trace:    errorDetails(test.Error).Body,
+		Suite:    ctrfSuitePath(names),
+		FilePath: test.Properties["file"],
+		Stdout:   ctrfLines(test.SystemOut),
+		Stderr:   ctrfLines(test.SystemErr),
+		Flaky:    test.Flaky(),
+	}
+
+	if test.Classname != "" {
+		ctrf.Extra = &ctrfExtra{Classname: test.Classname}
+	}
+
+	ctrf.Line, _ = strconv.Atoi(test.Properties["line"])
+
+	if tags := test.Properties["tags"]; tags != "" {
+		ctrf.Tags = strings.Fields(tags)
+	}
+
+	switch test.Status {
+	case StatusPassed:
+		ctrf.Status = "passed"
+		ctrf.Retries = len(test.Attempts)
+	case StatusSkipped:
+		ctrf.Status = "skipped"
+	case StatusFailed:
+		ctrf.Status = "failed"
+	case StatusError:
+		ctrf.Status = "failed"
+		ctrf.RawStatus = "error"
+	default:
+		ctrf.Status = "other"
+	}
+
+	if (test.Status == StatusFailed || test.Status == StatusError) && len(test.Attempts) > 1 {
+		ctrf.Retries = len(test.Attempts) - 1
+	}
+
+	return ctrf
+}
+
+type ctrfReport struct {
+	ReportFormat string      `json:"reportFormat,omitempty"`
+	SpecVersion  string      `json:"specVersion,omitempty"`
+	Results      ctrfResults `json:"results"`
+}
+
+type ctrfResults struct {
+	Tool struct {
+		Name string `json:"name"`
+	} `json:"tool"`
+	Summary ctrfSummary `json:"summary"`
+	Tests   []ctrfTest  `json:"tests"`
+}
+
+type ctrfSummary struct {
+	Tests   int   `json:"tests"`
+	Passed  int   `json:"passed"`
+	Failed  int   `json:"failed"`
+	Pending int   `json:"pending"`
+	Skipped int   `json:"skipped"`
+	Other   int   `json:"other"`
+	Start   int64 `json:"start"`
+	Stop    int64 `json:"stop"`
+}
+
+type ctrfTest struct {
+	Name      string        `json:"name"`
+	Status    string        `json:"status"`
+	Duration  float64       `json:"duration"`
+	Message   string        `json:"message,omitempty"`
+	Trace     string        `json:"trace,omitempty"`
+	RawStatus string        `json:"rawStatus,omitempty"`
+	Tags      []string      `json:"tags,omitempty"`
+	FilePath  string        `json:"filePath,omitempty"`
+	Line      int           `json:"line,omitempty"`
+	Retries   int           `json:"retries,omitempty"`
+	Flaky     bool          `json:"flaky,omitempty"`
+	Suite     ctrfSuitePath `json:"suite,omitempty"`
+	Stdout    []string      `json:"stdout,omitempty"`
+	Stderr    []string      `json:"stderr,omitempty"`
+	Extra     *ctrfExtra    `json:"extra,omitempty"`
+}
+
+// ctrfExtra holds the details of a test that CTRF has no field for.
+type ctrfExtra struct {
+	Classname string `json:"classname,omitempty"`
+}
+
+// ctrfSuitePath is the chain of suite names that a CTRF test is nested
+// within. It is written as an array of strings, but may also be read from a
+// single string, as used by earlier versions of CTRF.
+type ctrfSuitePath []string
+
+func (path *ctrfSuitePath) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err == nil {
+		*path = names
+
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	*path = nil
+	if name != "" {
+		*path = strings.Split(name, ctrfSuiteSeparator)
+	}
+
+	return nil
+}
+
+// ctrfTime returns the time of the given milliseconds since the Unix epoch,
+// or the zero time if none were given.
+func ctrfTime(milliseconds int64) time.Time {
+	if milliseconds == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, milliseconds*int64(time.Millisecond)).UTC()
+}
+
+// ctrfOutput joins the given lines of output.
+func ctrfOutput(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// ctrfLines splits the given output into lines.
+func ctrfLines(output string) []string {
+	if output == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+}
@@ -0,0 +1,145 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIngestCTRFFile(t *testing.T) {
+	suites, err := IngestCTRFFile("testdata/ctrf.json")
+	assertNoError(t, err)
+	assertLen(t, suites, 3)
+
+	spec := suites[0]
+	assertEqual(t, "dashboard.spec.ts", spec.Name)
+	assertEqual(t, time.Date(2024, 1, 30, 19, 47, 3, 0, time.UTC), spec.Timestamp)
+	assertLen(t, spec.Tests, 0)
+	assertLen(t, spec.Suites, 1)
+
+	dashboard := spec.Suites[0]
+	assertEqual(t, "Dashboard", dashboard.Name)
+	assertLen(t, dashboard.Tests, 2)
+	assertEqual(t, map[string]string{"file": "tests/dashboard.spec.ts", "line": "12", "tags": "@smoke"}, dashboard.Tests[0].Properties)
+	assertEqual(t, 3400500*time.Microsecond, dashboard.Tests[1].Duration)
+	assertEqual(t, true, dashboard.Tests[1].Flaky())
+	assertLen(t, dashboard.Tests[1].Attempts, 2)
+	assertEqual(t, "loading\nloaded\n", dashboard.Tests[1].SystemOut)
+	assertEqual(t, Totals{Tests: 2, Passed: 2, Duration: 4600500 * time.Microsecond}, spec.Totals)
+
+	login := suites[1]
+	assertEqual(t, "login.spec.ts", login.Name)
+	assertLen(t, login.Tests, 2)
+	assertEqual(t, StatusFailed, login.Tests[0].Status)
+	assertEqual(t, "expect(received).toBe(expected)", login.Tests[0].Message)
+	assertError(t, login.Tests[0].Error, "Error: expect(received).toBe(expected)\n    at tests/login.spec.ts:31:5")
+	assertEqual(t, []Attempt{
+		{Status: StatusFailed},
+		{
+			Status:   StatusFailed,
+			Rerun:    true,
+			Message:  "expect(received).toBe(expected)",
+			Body:     "Error: expect(received).toBe(expected)\n    at tests/login.spec.ts:31:5",
+			Duration: 5 * time.Second,
+		},
+	}, login.Tests[0].Attempts)
+	assertEqual(t, StatusSkipped, login.Tests[1].Status)
+
+	tool := suites[2]
+	assertEqual(t, "playwright", tool.Name)
+	assertEqual(t, StatusError, tool.Tests[0].Status)
+}
+
+func TestMarshalCTRF(t *testing.T) {
+	expected, err := IngestCTRFFile("testdata/ctrf.json")
+	assertNoError(t, err)
+
+	data, err := MarshalCTRF(expected, "playwright")
+	assertNoError(t, err)
+
+	actual, err := IngestCTRF(data)
+	assertNoError(t, err)
+
+	// Tests with an "other" status are ingested as errors, which are written
+	// as failed with a "rawStatus" of "error", and so are read back as errors.
+	assertEqual(t, expected, actual)
+}
+
+func TestMarshalCTRFSummary(t *testing.T) {
+	suites := []Suite{
+		{
+			Name:      "outer",
+			Timestamp: time.Date(2024, 1, 30, 19, 47, 3, 0, time.UTC),
+			Suites: []Suite{
+				{
+					Name: "inner",
+					Tests: []Test{
+						{Name: "a", Classname: "pkg.A", Status: StatusPassed, Duration: time.Second},
+						{Name: "b", Status: StatusError, Duration: time.Second, Error: Error{Body: "boom"}},
+						{Name: "c", Status: "unknown"},
+					},
+				},
+			},
+		},
+	}
+
+	data, err := MarshalCTRF(suites, "go")
+	assertNoError(t, err)
+	assertEqual(t, `{
+  "reportFormat": "CTRF",
+  "specVersion": "1.0.0",
+  "results": {
+    "tool": {
+      "name": "go"
+    },
+    "summary": {
+      "tests": 3,
+      "passed": 1,
+      "failed": 1,
+      "pending": 0,
+      "skipped": 0,
+      "other": 1,
+      "start": 1706644023000,
+      "stop": 1706644025000
+    },
+    "tests": [
+      {
+        "name": "a",
+        "status": "passed",
+        "duration": 1000,
+        "suite": [
+          "outer",
+          "inner"
+        ],
+        "extra": {
+          "classname": "pkg.A"
+        }
+      },
+      {
+        "name": "b",
+        "status": "failed",
+        "duration": 1000,
+        "trace": "boom",
+        "rawStatus": "error",
+        "suite": [
+          "outer",
+          "inner"
+        ]
+      },
+      {
+        "name": "c",
+        "status": "other",
+        "duration": 0,
+        "suite": [
+          "outer",
+          "inner"
+        ]
+      }
+    ]
+  }
+}`, string(data))
+}
@@ -0,0 +1,323 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IngestCucumber will parse the given Cucumber JSON data and return a slice of
+// test suite definitions, with one suite per feature. Reports written by
+// Cucumber, Behave, and Godog are supported.
+//
+// Each scenario becomes a test, with the feature name as its classname, and
+// the examples of each scenario outline are grouped into a nested suite named
+// after the outline. Background steps are attributed to the scenario that
+// follows them. A scenario fails if any of its steps or hooks failed, and the
+// first such failure becomes the test error, with the failing step as its
+// message. Scenarios with undefined or pending steps are considered skipped,
+// and scenarios with ambiguous steps are considered errors. The durations of
+// all steps and hooks are summed, where integral durations are assumed to be
+// in nanoseconds, and fractional durations in seconds. The scenario tags,
+// file, and line become the "tags", "file", and "line" test properties.
+func IngestCucumber(data []byte, opts ...Option) ([]Suite, error) {
+	return IngestCucumberReader(bytes.NewReader(data), opts...)
+}
+
+// IngestCucumberFile will parse the given Cucumber JSON file and return a
+// slice of test suite definitions.
+func IngestCucumberFile(filename string, opts ...Option) ([]Suite, error) {
+	file, err := os.Open(filename) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint
+
+	return IngestCucumberReader(file, opts...)
+}
+
+// IngestCucumberReader will parse the given Cucumber JSON reader and return a
+// slice of test suite definitions.
+func IngestCucumberReader(reader io.Reader, opts ...Option) ([]Suite, error) {
+	var features []cucumberFeature
+	if err := json.NewDecoder(reader).Decode(&features); err != nil {
+		return nil, err
+	}
+
+	suites := make([]Suite, len(features))
+	for index, feature := range features {
+		suites[index] = ingestCucumberFeature(feature)
+	}
+
+	return newOptions(opts).finish(suites)
+}
+
+type cucumberFeature struct {
+	URI      string            `json:"uri"`
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Elements []cucumberElement `json:"elements"`
+}
+
+type cucumberElement struct {
+	Keyword  string         `json:"keyword"`
+	Type     string         `json:"type"`
+	Name     string         `json:"name"`
+	Line     int            `json:"line"`
+	Location string         `json:"location"`
+	Tags     []cucumberTag  `json:"tags"`
+	Before   []cucumberStep `json:"before"`
+	Steps    []cucumberStep `json:"steps"`
+	After    []cucumberStep `json:"after"`
+}
+
+type cucumberStep struct {
+	Keyword string         `json:"keyword"`
+	Name    string         `json:"name"`
+	Result  cucumberResult `json:"result"`
+	Output  []string       `json:"output"`
+}
+
+type cucumberResult struct {
+	Status       string      `json:"status"`
+	Duration     json.Number `json:"duration"`
+	ErrorMessage string      `json:"error_message"`
+}
+
+// cucumberTag is the name of a tag, which Behave writes as a string, and
+// other tools write as an object.
+type cucumberTag string
+
+func (tag *cucumberTag) UnmarshalJSON(data []byte) error {
+	var object struct {
+		Name string `json:"name"`
+	}
+
+	if err := json.Unmarshal(data, &object); err == nil {
+		*tag = cucumberTag(object.Name)
+
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	*tag = cucumberTag(name)
+
+	return nil
+}
+
+func ingestCucumberFeature(feature cucumberFeature) Suite {
+	suite := Suite{
+		Name:    feature.Name,
+		Package: feature.URI,
+		ID:      feature.ID,
+	}
+
+	var (
+		background []cucumberStep
+
+		// outline is the index of the nested suite for the scenario outline
+		// that is currently being read, if any.
+		outline = -1
+	)
+
+	for _, element := range feature.Elements {
+		if element.Type == "background" {
+			background = append(background, element.Steps...)
+
+			continue
+		}
+
+		test := ingestCucumberScenario(feature, element, background)
+		background = nil
+
+		if element.Keyword != "Scenario Outline" && element.Keyword != "Scenario Template" {
+			suite.Tests = append(suite.Tests, test)
+			outline = -1
+
+			continue
+		}
+
+		// Examples of the same outline are written consecutively. Behave
+		// names each example after its outline, followed by " -- @".
+		name := element.Name
+		if index := strings.Index(name, " -- @"); index != -1 {
+			name = name[:index]
+		}
+
+		if outline == -1 || suite.Suites[outline].Name != name {
+			outline = len(suite.Suites)
+			suite.Suites = append(suite.Suites, Suite{
+				Name:    name,
+				Package: feature.URI,
+			})
+		}
+
+		suite.Suites[outline].Tests = append(suite.Suites[outline].Tests, test)
+	}
+
+	for index := range suite.Suites {
+		cucumberNumberExamples(suite.Suites[index].Tests)
+		suite.Suites[index].Aggregate()
+	}
+
+	suite.Aggregate()
+
+	return suite
+}
+
+func ingestCucumberScenario(feature cucumberFeature, element cucumberElement, background []cucumberStep) Test {
+	test := Test{
+		Name:       element.Name,
+		Classname:  feature.Name,
+		Status:     StatusPassed,
+		Properties: make(map[string]string),
+	}
+
+	file, line := feature.URI, strconv.Itoa(element.Line)
+	if index := strings.LastIndex(element.Location, ":"); index != -1 {
+		file, line = element.Location[:index], element.Location[index+1:]
+	}
+
+	if file != "" {
+		test.Properties["file"] = file
+	}
+
+	if line != "" && line != "0" {
+		test.Properties["line"] = line
+	}
+
+	if len(element.Tags) > 0 {
+		tags := make([]string, len(element.Tags))
+		for index, tag := range element.Tags {
+			tags[index] = string(tag)
+		}
+
+		test.Properties["tags"] = strings.Join(tags, " ")
+	}
+
+	var (
+		steps   []cucumberStep
+		skipped = true
+		output  []string
+	)
+
+	steps = append(steps, element.Before...)
+	steps = append(steps, background...)
+	steps = append(steps, element.Steps...)
+	steps = append(steps, element.After...)
+
+	for _, step := range steps {
+		test.Duration += cucumberDuration(step.Result.Duration)
+		output = append(output, step.Output...)
+
+		status := cucumberStatus(step.Result.Status)
+
+		if status != StatusSkipped {
+			skipped = false
+		}
+
+		switch {
+		case test.Status != StatusPassed:
+			// Only the first unsuccessful step is recorded.
+		case status == StatusFailed || status == StatusError:
+			test.Status = status
+			test.Message = cucumberStepText(step)
+			test.Error = Error{
+				Message: test.Message,
+				Body:    step.Result.ErrorMessage,
+			}
+		case step.Result.Status == "undefined":
+			test.Status = StatusSkipped
+			test.Message = "Undefined step: " + cucumberStepText(step)
+		case step.Result.Status == "pending":
+			test.Status = StatusSkipped
+			test.Message = "Pending step: " + cucumberStepText(step)
+		}
+	}
+
+	// A scenario whose steps were all skipped, such as with a tag filter, was
+	// never run.
+	if skipped && len(steps) > 0 && test.Status == StatusPassed {
+		test.Status = StatusSkipped
+	}
+
+	if details, ok := test.Error.(Error); ok {
+		test.Attempts = []Attempt{{
+			Status:   test.Status,
+			Message:  details.Message,
+			Body:     details.Body,
+			Duration: test.Duration,
+		}}
+	}
+
+	if len(output) > 0 {
+		test.SystemOut = strings.Join(output, "\n") + "\n"
+	}
+
+	return test
+}
+
+// cucumberStatus returns the status of a single step or hook.
+func cucumberStatus(status string) Status {
+	switch status {
+	case "passed":
+		return StatusPassed
+	case "failed":
+		return StatusFailed
+	case "ambiguous":
+		return StatusError
+	default:
+		return StatusSkipped
+	}
+}
+
+// cucumberStepText returns the text of the given step, such as "Given a
+// user", or "Hook" for hooks which have no text.
+func cucumberStepText(step cucumberStep) string {
+	text := strings.TrimSpace(strings.TrimSpace(step.Keyword) + " " + step.Name)
+	if text == "" {
+		return "Hook"
+	}
+
+	return text
+}
+
+// cucumberDuration parses a step duration. Integral durations are in
+// nanoseconds, as written by Cucumber and Godog, while fractional durations
+// are in seconds, as written by Behave.
+func cucumberDuration(number json.Number) time.Duration {
+	if nanoseconds, err := number.Int64(); err == nil {
+		return time.Duration(nanoseconds)
+	}
+
+	return duration(number.String())
+}
+
+// cucumberNumberExamples distinguishes the examples of a scenario outline
+// that share the same name, by appending their position to each name.
+func cucumberNumberExamples(tests []Test) {
+	names := make(map[string]int, len(tests))
+	for _, test := range tests {
+		names[test.Name]++
+	}
+
+	for index := range tests {
+		if names[tests[index].Name] > 1 {
+			tests[index].Name = fmt.Sprintf("%s #%d", tests[index].Name, index+1)
+		}
+	}
+}
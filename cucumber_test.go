@@ -0,0 +1,106 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIngestCucumberFile(t *testing.T) {
+	suites, err := IngestCucumberFile("testdata/cucumber.json")
+	assertNoError(t, err)
+	assertLen(t, suites, 1)
+
+	feature := suites[0]
+	assertEqual(t, "Login", feature.Name)
+	assertEqual(t, "features/login.feature", feature.Package)
+	assertLen(t, feature.Tests, 2)
+	assertLen(t, feature.Suites, 1)
+
+	valid := feature.Tests[0]
+	assertEqual(t, "Valid credentials", valid.Name)
+	assertEqual(t, "Login", valid.Classname)
+	assertEqual(t, StatusPassed, valid.Status)
+	assertEqual(t, 5*time.Millisecond, valid.Duration)
+	assertEqual(t, "signing in\n", valid.SystemOut)
+	assertEqual(t, map[string]string{
+		"file": "features/login.feature",
+		"line": "9",
+		"tags": "@auth @smoke",
+	}, valid.Properties)
+
+	invalid := feature.Tests[1]
+	assertEqual(t, StatusFailed, invalid.Status)
+	assertEqual(t, "Then I see an error", invalid.Message)
+	assertEqual(t, 6500*time.Microsecond, invalid.Duration)
+	assertEqual(t, Error{
+		Message: "Then I see an error",
+		Body:    "org.opentest4j.AssertionFailedError: expected: <true> but was: <false>\n\tat LoginSteps.seeError(LoginSteps.java:42)",
+	}, invalid.Error)
+	assertLen(t, invalid.Attempts, 1)
+
+	outline := feature.Suites[0]
+	assertEqual(t, "Locked accounts", outline.Name)
+	assertLen(t, outline.Tests, 2)
+	assertEqual(t, "Locked accounts #1", outline.Tests[0].Name)
+	assertEqual(t, "Locked accounts #2", outline.Tests[1].Name)
+	assertEqual(t, StatusSkipped, outline.Tests[1].Status)
+	assertEqual(t, `Undefined step: When I sign in as "carol"`, outline.Tests[1].Message)
+	assertEqual(t, Totals{Tests: 2, Passed: 1, Skipped: 1, Duration: time.Millisecond}, outline.Totals)
+
+	assertEqual(t, Totals{Tests: 4, Passed: 2, Skipped: 1, Failed: 1, Duration: 12500 * time.Microsecond}, feature.Totals)
+}
+
+func TestIngestCucumberBehave(t *testing.T) {
+	input := []byte(`[{
+		"keyword": "Feature",
+		"name": "Search",
+		"location": "features/search.feature:1",
+		"status": "failed",
+		"elements": [
+			{
+				"keyword": "Scenario Outline",
+				"name": "Find -- @1.1 Terms",
+				"location": "features/search.feature:9",
+				"tags": ["slow"],
+				"type": "scenario",
+				"steps": [
+					{"keyword": "When", "name": "I search", "result": {"status": "passed", "duration": 0.25}}
+				]
+			},
+			{
+				"keyword": "Scenario Outline",
+				"name": "Find -- @1.2 Terms",
+				"location": "features/search.feature:10",
+				"tags": ["slow"],
+				"type": "scenario",
+				"steps": [
+					{"keyword": "When", "name": "I search", "result": {"status": "ambiguous", "duration": 0.5}}
+				]
+			}
+		]
+	}]`)
+
+	suites, err := IngestCucumber(input)
+	assertNoError(t, err)
+	assertLen(t, suites, 1)
+	assertLen(t, suites[0].Suites, 1)
+
+	outline := suites[0].Suites[0]
+	assertEqual(t, "Find", outline.Name)
+	assertLen(t, outline.Tests, 2)
+	assertEqual(t, "Find -- @1.1 Terms", outline.Tests[0].Name)
+	assertEqual(t, 250*time.Millisecond, outline.Tests[0].Duration)
+	assertEqual(t, map[string]string{"file": "features/search.feature", "line": "9", "tags": "slow"}, outline.Tests[0].Properties)
+	assertEqual(t, StatusError, outline.Tests[1].Status)
+	assertEqual(t, "When I search", outline.Tests[1].Message)
+}
+
+func TestIngestCucumberMalformed(t *testing.T) {
+	_, err := IngestCucumber([]byte(`{"name": "not an array"}`))
+	assertError(t, err, "json: cannot unmarshal object into Go value of type []junit.cucumberFeature")
+}
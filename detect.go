@@ -0,0 +1,315 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Format represents a test report format that can be ingested.
+type Format string
+
+const (
+	// FormatUnknown represents data that is not in any recognized format.
+	FormatUnknown Format = ""
+
+	// FormatJUnit represents JUnit XML reports, as read by Ingest.
+	FormatJUnit Format = "junit"
+
+	// FormatTRX represents Visual Studio TRX reports, as read by IngestTRX.
+	FormatTRX Format = "trx"
+
+	// FormatNUnit represents NUnit 3 XML reports, as read by IngestNUnit.
+	FormatNUnit Format = "nunit"
+
+	// FormatXUnit represents xUnit.net v2 XML reports, as read by
+	// IngestXUnit.
+	FormatXUnit Format = "xunit"
+
+	// FormatTAP represents TAP (Test Anything Protocol) output, as read by
+	// IngestTAP.
+	FormatTAP Format = "tap"
+
+	// FormatGoTest represents "go test -json" output, as read by
+	// IngestGoTest.
+	FormatGoTest Format = "gotest"
+
+	// FormatCucumber represents Cucumber JSON reports, as read by
+	// IngestCucumber.
+	FormatCucumber Format = "cucumber"
+
+	// FormatCTRF represents CTRF (Common Test Report Format) JSON reports, as
+	// read by IngestCTRF.
+	FormatCTRF Format = "ctrf"
+
+	// FormatJSON represents suites encoded as JSON, as read by IngestJSON.
+	FormatJSON Format = "json"
+
+	// FormatYAML represents suites encoded as YAML, as read by IngestYAML.
+	FormatYAML Format = "yaml"
+)
+
+// yamlItemPattern matches the first line of a YAML sequence of mappings.
+var yamlItemPattern = regexp.MustCompile(`^- +[^\s#:]+:( |$)`) //nolint:gochecknoglobals
+
+// sniffLength is the number of leading bytes that are inspected in order to
+// detect the format of a report.
+const sniffLength = 8192
+
+// Report represents a single ingested file, along with its detected format.
+type Report struct {
+	// Filename is the path of the file.
+	Filename string `json:"filename" yaml:"filename"`
+
+	// Format is the format that the file was detected as.
+	Format Format `json:"format" yaml:"format"`
+
+	// Suites is all test suite definitions contained within the file.
+	Suites []Suite `json:"suites" yaml:"suites"`
+}
+
+// DetectFormat returns the format of the given report data, which only needs
+// to contain the beginning of the report. The root tag name is used to tell
+// apart XML formats, and JUnit XML is also recognized by a "testsuite" tag
+// nested anywhere within another root tag. The presence of well-known keys is
+// used to tell apart JSON formats, and TAP is recognized by its version line,
+// or by both a plan and a test point. Suites encoded by MarshalJSON and
+// MarshalYAML are also recognized.
+func DetectFormat(data []byte) Format {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	trimmed := bytes.TrimSpace(data)
+
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return detectXMLFormat(trimmed)
+
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		switch {
+		case bytes.Contains(trimmed, []byte(`"declared":`)):
+			return FormatJSON
+		case bytes.Contains(trimmed, []byte(`"elements"`)) || bytes.Contains(trimmed, []byte(`"keyword"`)):
+			return FormatCucumber
+		default:
+			return FormatUnknown
+		}
+	}
+
+	for _, line := range bytes.Split(trimmed, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+
+		switch {
+		case len(line) == 0, bytes.HasPrefix(line, []byte("#")), bytes.Equal(line, []byte("---")):
+			// Comments may precede TAP, YAML, and (as build errors) go test
+			// output, so are inconclusive, as are YAML document markers.
+			continue
+
+		case bytes.HasPrefix(line, []byte("{")):
+			if bytes.Contains(line, []byte(`"Action"`)) {
+				return FormatGoTest
+			}
+
+			// CTRF reports nest the tool that produced them within their
+			// results, which distinguishes them from other JSON documents.
+			if bytes.Contains(trimmed, []byte(`"results"`)) && bytes.Contains(trimmed, []byte(`"tool"`)) {
+				return FormatCTRF
+			}
+
+		case bytes.HasPrefix(line, []byte("TAP version")):
+			return FormatTAP
+
+		case tapPlanPattern.Match(line), tapTestPattern.Match(line):
+			return detectTAP(trimmed)
+
+		case yamlItemPattern.Match(line):
+			if bytes.Contains(trimmed, []byte("\n  declared:")) {
+				return FormatYAML
+			}
+		}
+
+		// Otherwise, the first significant line is enough to decide.
+		return FormatUnknown
+	}
+
+	return FormatUnknown
+}
+
+// detectXMLFormat returns the format of the given XML data, based on the name
+// of its root tag. Like Ingest, a "testsuite" tag nested within any other root
+// tag is recognized as JUnit XML.
+func detectXMLFormat(data []byte) Format {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	// Only tag names are needed, so the declared encoding is irrelevant.
+	dec.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	root := true
+
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return FormatUnknown
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch name := start.Name.Local; {
+		case name == "testsuites", name == "testsuite":
+			return FormatJUnit
+		case !root:
+			continue
+		case name == "TestRun":
+			return FormatTRX
+		case name == "test-run":
+			return FormatNUnit
+		case name == "assemblies", name == "assembly":
+			return FormatXUnit
+		}
+
+		root = false
+	}
+}
+
+// detectTAP returns FormatTAP if the given data, which lacks a version line,
+// contains both a plan and a test point, since either alone is too easily
+// found in arbitrary text.
+func detectTAP(data []byte) Format {
+	var plan, test bool
+
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+
+		switch {
+		case tapPlanPattern.Match(line):
+			plan = true
+		case tapTestPattern.Match(line):
+			test = true
+		}
+
+		if plan && test {
+			return FormatTAP
+		}
+	}
+
+	return FormatUnknown
+}
+
+// IngestFormat will parse the given reader as a report of the given format,
+// and return a slice of all contained test suite definitions. The given
+// options are passed to the ingester of that format.
+func IngestFormat(reader io.Reader, format Format, opts ...Option) ([]Suite, error) {
+	switch format {
+	case FormatJUnit:
+		return IngestReader(reader, opts...)
+	case FormatTRX:
+		return IngestTRXReader(reader, opts...)
+	case FormatNUnit:
+		return IngestNUnitReader(reader, opts...)
+	case FormatXUnit:
+		return IngestXUnitReader(reader, opts...)
+	case FormatTAP:
+		return IngestTAPReader(reader, opts...)
+	case FormatGoTest:
+		return IngestGoTestReader(reader, opts...)
+	case FormatCucumber:
+		return IngestCucumberReader(reader, opts...)
+	case FormatCTRF:
+		return IngestCTRFReader(reader, opts...)
+	case FormatJSON:
+		return IngestJSONReader(reader, opts...)
+	case FormatYAML:
+		return IngestYAMLReader(reader, opts...)
+	default:
+		return nil, fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+// IngestFileAuto will detect the format of the given file, and parse it
+// accordingly. If the format is not recognized, the returned report has an
+// unknown format and no suites.
+func IngestFileAuto(filename string, opts ...Option) (Report, error) {
+	file, err := os.Open(filename) //nolint:gosec
+	if err != nil {
+		return Report{}, err
+	}
+	defer file.Close() //nolint
+
+	reader := bufio.NewReaderSize(file, sniffLength)
+
+	prefix, err := reader.Peek(sniffLength)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return Report{}, err
+	}
+
+	report := Report{
+		Filename: filename,
+		Format:   DetectFormat(prefix),
+	}
+
+	if report.Format == FormatUnknown {
+		return report, nil
+	}
+
+	suites, err := IngestFormat(reader, report.Format, opts...)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report.Suites = suites
+
+	return report, nil
+}
+
+// IngestDirAuto will search the given directory for reports in any recognized
+// format, and return a report for each file, in lexical order. Unlike
+// IngestDir, every file is inspected by default, and files whose format is not
+// recognized are omitted. Which files are inspected can be configured using
+// WithFileFilter.
+func IngestDirAuto(directory string, opts ...Option) ([]Report, error) {
+	// Inspect every file, unless a filter was given.
+	config := newOptions(append([]Option{WithFileFilter(func(string) bool {
+		return true
+	})}, opts...))
+
+	reports := make([]Report, 0)
+
+	err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() || !config.filter(path) {
+			return nil
+		}
+
+		report, err := IngestFileAuto(path, opts...)
+		if err != nil {
+			return err
+		}
+
+		if report.Format != FormatUnknown {
+			reports = append(reports, report)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,95 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		title    string
+		input    string
+		expected Format
+	}{
+		{"empty", "", FormatUnknown},
+		{"junit suites", `<?xml version="1.0"?><testsuites></testsuites>`, FormatJUnit},
+		{"junit suite with comment", "<!-- report -->\n<testsuite name=\"a\">", FormatJUnit},
+		{"junit latin-1", `<?xml version="1.0" encoding="ISO-8859-1"?><testsuite>`, FormatJUnit},
+		{"trx with byte order mark", "\xef\xbb\xbf<?xml version=\"1.0\"?><TestRun id=\"1\">", FormatTRX},
+		{"nunit", `<test-run id="0">`, FormatNUnit},
+		{"xunit", `<assemblies>`, FormatXUnit},
+		{"junit nested suite", `<report><results><testsuite name="a">`, FormatJUnit},
+		{"nunit nested suite", `<test-run id="0"><testsuite name="a">`, FormatNUnit},
+		{"unknown xml", `<html><body>`, FormatUnknown},
+		{"tap version", "TAP version 14\n1..1\n", FormatTAP},
+		{"tap plan", "1..3\nok 1\n", FormatTAP},
+		{"tap comment", "# tests\nnot ok 1 - broken\n1..1\n", FormatTAP},
+		{"tap test point only", "ok 1 - works\n", FormatUnknown},
+		{"tap plan only", "1..3 items\n", FormatUnknown},
+		{"go test", `{"Time":"2021-01-01T00:00:00Z","Action":"start","Package":"pkg"}`, FormatGoTest},
+		{"go test after build errors", "# pkg\n{\"Action\":\"output\",\"Package\":\"pkg\"}\n", FormatGoTest},
+		{"cucumber", `[{"uri": "a.feature", "elements": []}]`, FormatCucumber},
+		{"ctrf", "{\n  \"results\": {\n    \"tool\": {\"name\": \"jest\"}", FormatCTRF},
+		{"unknown json", `{"name": "value"}`, FormatUnknown},
+		{"json with results", `{"results": [1, 2, 3]}`, FormatUnknown},
+		{"json", `[{"name": "suite", "package": "", "declared": {}}]`, FormatJSON},
+		{"yaml", "- name: suite\n  package: pkg\n  declared:\n    tests: 0\n", FormatYAML},
+		{"yaml with document marker", "# suites\n---\n- name: suite\n  declared: {}\n", FormatYAML},
+		{"unknown yaml", "- name: build\n  run: make\n", FormatUnknown},
+		{"plain text", "hello world\nok 1\n", FormatUnknown},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			assertEqual(t, test.expected, DetectFormat([]byte(test.input)))
+		})
+	}
+}
+
+func TestIngestDirAuto(t *testing.T) {
+	reports, err := IngestDirAuto("testdata")
+	assertNoError(t, err)
+
+	formats := make(map[string]Format, len(reports))
+	for _, report := range reports {
+		formats[filepath.Base(report.Filename)] = report.Format
+	}
+
+	assertEqual(t, FormatTRX, formats["dotnet.trx"])
+	assertEqual(t, FormatNUnit, formats["nunit3.xml"])
+	assertEqual(t, FormatXUnit, formats["xunit2.xml"])
+	assertEqual(t, FormatTAP, formats["tap.tap"])
+	assertEqual(t, FormatGoTest, formats["go-test.json"])
+	assertEqual(t, FormatCucumber, formats["cucumber.json"])
+	assertEqual(t, FormatCTRF, formats["ctrf.json"])
+	assertEqual(t, FormatJUnit, formats["surefire.xml"])
+
+	for _, report := range reports {
+		if strings.HasSuffix(report.Filename, ".xml") && report.Format == FormatJUnit {
+			expected, err := IngestFile(report.Filename)
+			assertNoError(t, err)
+			assertEqual(t, expected, report.Suites)
+		}
+	}
+}
+
+func TestIngestFileAuto(t *testing.T) {
+	report, err := IngestFileAuto("testdata/dotnet.trx")
+	assertNoError(t, err)
+	assertEqual(t, FormatTRX, report.Format)
+	assertLen(t, report.Suites, 2)
+
+	_, err = IngestFileAuto("testdata/missing.xml")
+	assertError(t, err, "open testdata/missing.xml: no such file or directory")
+}
+
+func TestIngestFormat(t *testing.T) {
+	_, err := IngestFormat(strings.NewReader(""), FormatUnknown)
+	assertError(t, err, `unknown report format ""`)
+}
@@ -0,0 +1,158 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+// PropertyStyle represents how test properties are written by an encoder.
+type PropertyStyle string
+
+const (
+	// PropertiesAsAttributes writes test properties as attributes on the
+	// "testcase" tag.
+	PropertiesAsAttributes PropertyStyle = "attributes"
+
+	// PropertiesAsElements writes test properties as "property" tags within
+	// a "properties" tag, nested inside of the "testcase" tag.
+	PropertiesAsElements PropertyStyle = "elements"
+
+	// PropertiesOmitted does not write test properties at all.
+	PropertiesOmitted PropertyStyle = "omitted"
+)
+
+// Dialect describes a particular flavor of JUnit XML report. Consumers of
+// JUnit XML reports disagree on the schema that they accept, so a dialect
+// controls which parts of a suite are written by an encoder, and how.
+type Dialect struct {
+	// Name is a short identifier for the dialect.
+	Name string
+
+	// Flatten writes all nested suites as top-level suites, for consumers
+	// that do not support nesting. Suites that only contain other suites are
+	// omitted, and nested suites inherit the package of their parent.
+	Flatten bool
+
+	// Attributes writes all original suite attributes verbatim, rather than
+	// only the standard attributes. For suites that have original attributes,
+	// this includes any declared counts or lack thereof, in place of counts
+	// computed from the suite totals.
+	Attributes bool
+
+	// Metadata writes "timestamp" and "hostname" attributes on every suite.
+	Metadata bool
+
+	// Skipped writes a "skipped" tag for skipped tests. Otherwise, skipped
+	// tests are written without any result tag.
+	Skipped bool
+
+	// Reruns writes the Surefire "rerunFailure", "rerunError",
+	// "flakyFailure", and "flakyError" tags for tests that were rerun.
+	Reruns bool
+
+	// TestOutput writes "system-out" and "system-err" tags for tests.
+	// Otherwise, test output is only written at the suite level.
+	TestOutput bool
+
+	// TestProperties controls how test properties are written.
+	TestProperties PropertyStyle
+
+	// Strict writes every attribute and tag that is required by the JUnit XSD,
+	// even when empty, and omits any attribute that the XSD does not allow.
+	Strict bool
+}
+
+// DialectDefault returns the dialect used by Marshal. It retains everything
+// needed to ingest an identical set of suites.
+func DialectDefault() Dialect {
+	return Dialect{
+		Name:           "default",
+		Attributes:     true,
+		Skipped:        true,
+		Reruns:         true,
+		TestOutput:     true,
+		TestProperties: PropertiesAsAttributes,
+	}
+}
+
+// DialectSurefire returns the flavor produced by the Ant JUnit task and by
+// the Maven Surefire plugin.
+func DialectSurefire() Dialect {
+	return Dialect{
+		Name:           "surefire",
+		Flatten:        true,
+		Metadata:       true,
+		Skipped:        true,
+		Reruns:         true,
+		TestOutput:     true,
+		TestProperties: PropertiesOmitted,
+	}
+}
+
+// DialectJenkins returns the flavor accepted by the Jenkins JUnit plugin,
+// which is the Ant and Surefire flavor, with properties also written on
+// tests.
+func DialectJenkins() Dialect {
+	dialect := DialectSurefire()
+	dialect.Name = "jenkins"
+	dialect.TestProperties = PropertiesAsElements
+
+	return dialect
+}
+
+// DialectGitLab returns the flavor accepted by GitLab unit test reports,
+// which ignore nested suites, and read test attributes such as "file".
+func DialectGitLab() Dialect {
+	return Dialect{
+		Name:           "gitlab",
+		Flatten:        true,
+		Skipped:        true,
+		TestOutput:     true,
+		TestProperties: PropertiesAsAttributes,
+	}
+}
+
+// DialectAzure returns the flavor accepted by the Azure DevOps publish test
+// results task, which expects suite timestamps and hostnames.
+func DialectAzure() Dialect {
+	return Dialect{
+		Name:           "azure",
+		Flatten:        true,
+		Metadata:       true,
+		Skipped:        true,
+		TestOutput:     true,
+		TestProperties: PropertiesAsAttributes,
+	}
+}
+
+// DialectXSD returns the flavor described by the Windy Road JUnit XSD. It is
+// the strictest dialect, and is unable to represent skipped tests, test
+// output, test properties, or more than a single result per test.
+func DialectXSD() Dialect {
+	return Dialect{
+		Name:           "xsd",
+		Flatten:        true,
+		Metadata:       true,
+		TestProperties: PropertiesOmitted,
+		Strict:         true,
+	}
+}
+
+// LookupDialect returns the predefined dialect with the given name, and
+// whether such a dialect exists.
+func LookupDialect(name string) (Dialect, bool) {
+	for _, dialect := range []Dialect{
+		DialectDefault(),
+		DialectSurefire(),
+		DialectJenkins(),
+		DialectGitLab(),
+		DialectAzure(),
+		DialectXSD(),
+	} {
+		if dialect.Name == name {
+			return dialect, true
+		}
+	}
+
+	return Dialect{}, false
+}
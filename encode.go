@@ -0,0 +1,618 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Marshal will encode the given suites as a JUnit XML document.
+//
+// Documents produced by Marshal can be ingested again without loss, such that
+// ingesting the marshalled form of previously ingested suites results in the
+// same suites.
+func Marshal(suites []Suite) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := NewEncoder(&buf).Encode(suites); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Encoder writes JUnit XML documents to an output stream.
+type Encoder struct {
+	writer  io.Writer
+	dialect Dialect
+	now     func() time.Time
+}
+
+// NewEncoder returns a new encoder that writes to the given writer, using the
+// default dialect.
+func NewEncoder(writer io.Writer) *Encoder {
+	return &Encoder{
+		writer:  writer,
+		dialect: DialectDefault(),
+		now:     time.Now,
+	}
+}
+
+// SetDialect changes the dialect used for all subsequent documents written by
+// the encoder.
+func (enc *Encoder) SetDialect(dialect Dialect) {
+	enc.dialect = dialect
+}
+
+// Encode writes the given suites to the stream as a single JUnit XML
+// document, with a top-level "testsuites" tag.
+//
+// Suite count attributes are computed from the suite totals, which are first
+// recalculated from the tests contained within each suite. Dialects that
+// write the original suite attributes instead retain any declared counts for
+// suites that have attributes.
+func (enc *Encoder) Encode(suites []Suite) error {
+	if _, err := io.WriteString(enc.writer, xml.Header); err != nil {
+		return err
+	}
+
+	xenc := &encoder{
+		Encoder: xml.NewEncoder(enc.writer),
+		dialect: enc.dialect,
+		now:     enc.now,
+	}
+
+	xenc.Indent("", "\t")
+
+	if enc.dialect.Flatten {
+		suites = flatten(suites, "")
+	}
+
+	var totals Totals
+
+	aggregated := make([]Suite, len(suites))
+	for index, suite := range suites {
+		suite.Aggregate()
+		aggregated[index] = suite
+		totals = totals.add(suite.Totals)
+	}
+
+	start := xml.StartElement{
+		Name: xml.Name{Local: "testsuites"},
+	}
+
+	if !enc.dialect.Strict {
+		start.Attr = totalsAttrs(totals, true)
+	}
+
+	if err := xenc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for index, suite := range aggregated {
+		if err := xenc.encodeSuite(suite, index); err != nil {
+			return err
+		}
+	}
+
+	if err := xenc.EncodeToken(start.End()); err != nil {
+		return err
+	}
+
+	if err := xenc.Flush(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(enc.writer, "\n")
+
+	return err
+}
+
+// flatten returns all of the given suites, and all of their nested suites, as
+// a single list without any nesting. Suites that only contain other suites
+// are omitted, and nested suites inherit the package of their parent.
+func flatten(suites []Suite, pkg string) []Suite {
+	var flattened []Suite
+
+	for _, suite := range suites {
+		if suite.Package == "" {
+			suite.Package = pkg
+		}
+
+		nested := suite.Suites
+		suite.Suites = nil
+
+		if len(suite.Tests) > 0 || len(nested) == 0 || suite.SystemOut != "" || suite.SystemErr != "" {
+			flattened = append(flattened, suite)
+		}
+
+		flattened = append(flattened, flatten(nested, suite.Package)...)
+	}
+
+	return flattened
+}
+
+// encoder wraps an XML token stream, and writes suites to that stream
+// according to a particular dialect.
+type encoder struct {
+	*xml.Encoder
+	dialect Dialect
+	now     func() time.Time
+}
+
+// encodeSuite writes the given suite, and all of its tests and nested suites.
+// The suite totals are assumed to have already been aggregated.
+func (enc *encoder) encodeSuite(suite Suite, index int) error {
+	var (
+		strict = enc.dialect.Strict
+		attrs  = make(map[string]string, len(suite.Attributes)+10)
+	)
+
+	// Original attributes are written verbatim, including any declared counts
+	// or lack thereof, so that the suite can be ingested again without loss.
+	verbatim := enc.dialect.Attributes && suite.Attributes != nil
+	if verbatim {
+		for name, value := range suite.Attributes {
+			attrs[name] = value
+		}
+	}
+
+	if _, found := attrs["name"]; found || suite.Name != "" || strict {
+		attrs["name"] = suite.Name
+	}
+
+	if _, found := attrs["package"]; found || suite.Package != "" || strict {
+		attrs["package"] = suite.Package
+	}
+
+	if _, found := attrs["id"]; found || suite.ID != "" {
+		attrs["id"] = suite.ID
+	}
+
+	if _, found := attrs["hostname"]; found || suite.Hostname != "" {
+		attrs["hostname"] = suite.Hostname
+	}
+
+	// Preserve the original timestamp attribute if it is still accurate, as
+	// there are several different ways to format the same time.
+	if timespec, found := attrs["timestamp"]; (found && !timestamp(timespec).Equal(suite.Timestamp)) || (!found && !suite.Timestamp.IsZero()) {
+		attrs["timestamp"] = suite.Timestamp.Format(time.RFC3339Nano)
+	}
+
+	if strict {
+		attrs["id"] = strconv.Itoa(index)
+	}
+
+	if enc.dialect.Metadata {
+		attrs["timestamp"] = enc.timestamp(suite)
+		attrs["hostname"] = hostname(suite)
+	}
+
+	if !verbatim {
+		for _, attr := range totalsAttrs(suite.Totals, !strict) {
+			attrs[attr.Name.Local] = attr.Value
+		}
+	}
+
+	start := xml.StartElement{
+		Name: xml.Name{Local: "testsuite"},
+		Attr: orderedAttrs(attrs, "name", "package", "id", "timestamp", "hostname",
+			"tests", "failures", "errors", "skipped", "time"),
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if suite.Properties != nil || strict {
+		if err := enc.encodeProperties(suite.Properties, nil); err != nil {
+			return err
+		}
+	}
+
+	for _, test := range suite.Tests {
+		if err := enc.encodeTestcase(test); err != nil {
+			return err
+		}
+	}
+
+	for index, nested := range suite.Suites {
+		nested.Aggregate()
+		if err := enc.encodeSuite(nested, index); err != nil {
+			return err
+		}
+	}
+
+	if suite.SystemOut != "" || strict {
+		if err := enc.encodeElement("system-out", nil, suite.SystemOut); err != nil {
+			return err
+		}
+	}
+
+	if suite.SystemErr != "" || strict {
+		if err := enc.encodeElement("system-err", nil, suite.SystemErr); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// timestamp returns the time at which the given suite was run, falling back
+// to the current time when that is unknown. Times are formatted in UTC,
+// without a timezone, as required by the JUnit XSD.
+func (enc *encoder) timestamp(suite Suite) string {
+	if suite.Timestamp.IsZero() {
+		return enc.now().UTC().Format("2006-01-02T15:04:05")
+	}
+
+	return suite.Timestamp.UTC().Format("2006-01-02T15:04:05")
+}
+
+// hostname returns the name of the host on which the given suite was run,
+// falling back to the current hostname when that is unknown.
+func hostname(suite Suite) string {
+	if suite.Hostname != "" {
+		return suite.Hostname
+	}
+
+	if hostname, err := os.Hostname(); err == nil {
+		return hostname
+	}
+
+	return "localhost"
+}
+
+// encodeProperties writes the given properties as a "properties" tag, with
+// one "property" tag per entry, ordered by name. Any of the given excluded
+// properties are not written.
+func (enc *encoder) encodeProperties(props map[string]string, exclude []string) error {
+	start := xml.StartElement{Name: xml.Name{Local: "properties"}}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, name := range sortedKeys(props) {
+		if contains(exclude, name) {
+			continue
+		}
+
+		property := xml.StartElement{
+			Name: xml.Name{Local: "property"},
+			Attr: []xml.Attr{
+				xmlAttr("name", name),
+				xmlAttr("value", props[name]),
+			},
+		}
+
+		if err := enc.EncodeToken(property); err != nil {
+			return err
+		}
+
+		if err := enc.EncodeToken(property.End()); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// encodeTestcase writes the given test as a "testcase" tag. Depending on the
+// dialect, test properties are written either as attributes alongside the
+// test name, classname, and time, or as a nested "properties" tag.
+func (enc *encoder) encodeTestcase(test Test) error {
+	var (
+		strict   = enc.dialect.Strict
+		standard = []string{"name", "classname", "time"}
+		attrs    = make(map[string]string, len(test.Properties)+3)
+	)
+
+	for name, value := range test.Properties {
+		if enc.dialect.TestProperties == PropertiesAsAttributes || contains(standard, name) {
+			attrs[name] = value
+		}
+	}
+
+	if _, found := attrs["name"]; found || test.Name != "" || strict {
+		attrs["name"] = test.Name
+	}
+
+	if _, found := attrs["classname"]; found || test.Classname != "" || strict {
+		attrs["classname"] = test.Classname
+	}
+
+	// Preserve the original time attribute if it is still accurate, as there
+	// are several different ways to format the same duration.
+	if timespec, found := attrs["time"]; (found && duration(timespec) != test.Duration) || (!found && (test.Duration != 0 || strict)) {
+		attrs["time"] = formatDuration(test.Duration)
+	}
+
+	start := xml.StartElement{
+		Name: xml.Name{Local: "testcase"},
+		Attr: orderedAttrs(attrs, standard...),
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if enc.dialect.TestProperties == PropertiesAsElements && len(test.Properties) > 0 {
+		if err := enc.encodeProperties(test.Properties, standard); err != nil {
+			return err
+		}
+	}
+
+	if err := enc.encodeResult(test); err != nil {
+		return err
+	}
+
+	if test.SystemOut != "" && enc.dialect.TestOutput {
+		if err := enc.encodeElement("system-out", nil, test.SystemOut); err != nil {
+			return err
+		}
+	}
+
+	if test.SystemErr != "" && enc.dialect.TestOutput {
+		if err := enc.encodeElement("system-err", nil, test.SystemErr); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// encodeResult writes tags for the result of the given test, and for every
+// recorded attempt at running it. Nothing is written for passed tests that
+// were never rerun.
+func (enc *encoder) encodeResult(test Test) error {
+	var last *Attempt
+
+	for index := range test.Attempts {
+		if !test.Attempts[index].Rerun {
+			last = &test.Attempts[index]
+		}
+	}
+
+	// The initial attempts are written in place of the status of the test, but
+	// only if they still agree with that status, as the last initial attempt
+	// determines the status when ingested. The XSD only allows a single result.
+	initial := last != nil && last.Status == test.Status && !enc.dialect.Strict
+
+	if !initial {
+		if err := enc.encodeStatus(test); err != nil {
+			return err
+		}
+	}
+
+	for _, attempt := range test.Attempts {
+		if enc.dialect.Strict || (!initial && !attempt.Rerun) || (attempt.Rerun && !enc.dialect.Reruns) {
+			continue
+		}
+
+		if err := enc.encodeAttempt(test, attempt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeAttempt writes a "failure" or "error" tag for the given attempt, or
+// one of the equivalent Surefire rerun tags if the attempt was a rerun.
+func (enc *encoder) encodeAttempt(test Test, attempt Attempt) error {
+	var name string
+
+	switch {
+	case !attempt.Rerun && attempt.Status == StatusError:
+		name = "error"
+	case !attempt.Rerun:
+		name = "failure"
+	case test.Status == StatusPassed && attempt.Status == StatusError:
+		name = "flakyError"
+	case test.Status == StatusPassed:
+		name = "flakyFailure"
+	case attempt.Status == StatusError:
+		name = "rerunError"
+	default:
+		name = "rerunFailure"
+	}
+
+	var attrs []xml.Attr
+	if attempt.Message != "" {
+		attrs = append(attrs, xmlAttr("message", attempt.Message))
+	}
+
+	if attempt.Type != "" {
+		attrs = append(attrs, xmlAttr("type", attempt.Type))
+	}
+
+	if attempt.Duration != 0 {
+		attrs = append(attrs, xmlAttr("time", formatDuration(attempt.Duration)))
+	}
+
+	start := xml.StartElement{
+		Name: xml.Name{Local: name},
+		Attr: attrs,
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if attempt.Body != "" {
+		if err := enc.EncodeToken(xml.CharData(attempt.Body)); err != nil {
+			return err
+		}
+	}
+
+	for _, child := range []struct {
+		name string
+		text string
+	}{
+		{"stackTrace", attempt.StackTrace},
+		{"system-out", attempt.SystemOut},
+		{"system-err", attempt.SystemErr},
+	} {
+		if child.text == "" {
+			continue
+		}
+
+		if err := enc.encodeElement(child.name, nil, child.text); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// encodeStatus writes a "skipped", "failure", or "error" tag corresponding to
+// the status of the given test. Nothing is written for passed tests.
+func (enc *encoder) encodeStatus(test Test) error {
+	var name string
+
+	switch test.Status {
+	case StatusSkipped:
+		if !enc.dialect.Skipped {
+			return nil
+		}
+
+		var attrs []xml.Attr
+		if test.Message != "" {
+			attrs = append(attrs, xmlAttr("message", test.Message))
+		}
+
+		return enc.encodeElement("skipped", attrs, "")
+	case StatusFailed:
+		name = "failure"
+	case StatusError:
+		name = "error"
+	default:
+		return nil
+	}
+
+	details := errorDetails(test.Error)
+	if details.Message == "" {
+		details.Message = test.Message
+	}
+
+	var attrs []xml.Attr
+	if details.Message != "" || enc.dialect.Strict {
+		attrs = append(attrs, xmlAttr("message", details.Message))
+	}
+
+	if details.Type != "" || enc.dialect.Strict {
+		attrs = append(attrs, xmlAttr("type", details.Type))
+	}
+
+	return enc.encodeElement(name, attrs, details.Body)
+}
+
+// encodeElement writes a tag with the given name and attributes, containing
+// the given text.
+func (enc *encoder) encodeElement(name string, attrs []xml.Attr, text string) error {
+	start := xml.StartElement{
+		Name: xml.Name{Local: name},
+		Attr: attrs,
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if text != "" {
+		if err := enc.EncodeToken(xml.CharData(text)); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// totalsAttrs returns the standard count attributes for the given totals.
+// The skipped count is optional, as it is not universally supported.
+func totalsAttrs(totals Totals, skipped bool) []xml.Attr {
+	attrs := []xml.Attr{
+		xmlAttr("tests", strconv.Itoa(totals.Tests)),
+		xmlAttr("failures", strconv.Itoa(totals.Failed)),
+		xmlAttr("errors", strconv.Itoa(totals.Error)),
+	}
+
+	if skipped {
+		attrs = append(attrs, xmlAttr("skipped", strconv.Itoa(totals.Skipped)))
+	}
+
+	return append(attrs, xmlAttr("time", formatDuration(totals.Duration)))
+}
+
+// orderedAttrs returns the given attributes with the named attributes first,
+// followed by all remaining attributes ordered by name.
+func orderedAttrs(attrs map[string]string, first ...string) []xml.Attr {
+	ordered := make([]xml.Attr, 0, len(attrs))
+
+	for _, name := range first {
+		if value, found := attrs[name]; found {
+			ordered = append(ordered, xmlAttr(name, value))
+		}
+	}
+
+	for _, name := range sortedKeys(attrs) {
+		if !contains(first, name) {
+			ordered = append(ordered, xmlAttr(name, attrs[name]))
+		}
+	}
+
+	return ordered
+}
+
+func xmlAttr(name, value string) xml.Attr {
+	return xml.Attr{
+		Name:  xml.Name{Local: name},
+		Value: value,
+	}
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatDuration formats the given duration as a decimal number of seconds,
+// using the fewest digits that still ingest back into the same duration.
+func formatDuration(d time.Duration) string {
+	seconds := d.Seconds()
+
+	for precision := 3; precision < 9; precision++ {
+		timespec := strconv.FormatFloat(seconds, 'f', precision, 64)
+		if duration(timespec) == d {
+			return timespec
+		}
+	}
+
+	return strconv.FormatFloat(seconds, 'f', 9, 64)
+}
@@ -0,0 +1,306 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	filenames, err := filepath.Glob("testdata/*.xml")
+	assertNoError(t, err)
+
+	for _, filename := range filenames {
+		filename := filename
+
+		t.Run(filename, func(t *testing.T) {
+			expected, err := IngestFile(filename)
+			assertNoError(t, err)
+
+			data, err := Marshal(expected)
+			assertNoError(t, err)
+
+			actual, err := Ingest(data)
+			assertNoError(t, err)
+
+			assertEqual(t, expected, actual)
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	suites := []Suite{
+		{
+			Name:    "suite",
+			Package: "example",
+			Tests: []Test{
+				{
+					Name:      "passed",
+					Classname: "Example",
+					Duration:  1500 * time.Millisecond,
+					Status:    StatusPassed,
+					SystemOut: "stdout <text>",
+				},
+				{
+					Name:    "skipped",
+					Status:  StatusSkipped,
+					Message: "not today",
+				},
+				{
+					Name:   "failed",
+					Status: StatusFailed,
+					Error: Error{
+						Message: "expected true",
+						Type:    "AssertionError",
+						Body:    "at example.go:12",
+					},
+				},
+			},
+		},
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="3" failures="1" errors="0" skipped="1" time="1.500">
+	<testsuite name="suite" package="example" tests="3" failures="1" errors="0" skipped="1" time="1.500">
+		<testcase name="passed" classname="Example" time="1.500">
+			<system-out>stdout &lt;text&gt;</system-out>
+		</testcase>
+		<testcase name="skipped">
+			<skipped message="not today"></skipped>
+		</testcase>
+		<testcase name="failed">
+			<failure message="expected true" type="AssertionError">at example.go:12</failure>
+		</testcase>
+	</testsuite>
+</testsuites>
+`
+
+	actual, err := Marshal(suites)
+	assertNoError(t, err)
+	assertEqual(t, expected, string(actual))
+}
+
+func TestMarshalDeclaredCounts(t *testing.T) {
+	suites := []Suite{
+		{
+			Name: "suite",
+			Attributes: map[string]string{
+				"name":     "suite",
+				"tests":    "5",
+				"failures": "2",
+				"skips":    "1",
+				"time":     "9.000",
+				"vendor":   "example",
+			},
+			Tests: []Test{
+				{Name: "passed", Status: StatusPassed},
+			},
+		},
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="1" failures="0" errors="0" skipped="0" time="0.000">
+	<testsuite name="suite" tests="5" failures="2" time="9.000" skips="1" vendor="example">
+		<testcase name="passed"></testcase>
+	</testsuite>
+</testsuites>
+`
+
+	actual, err := Marshal(suites)
+	assertNoError(t, err)
+	assertEqual(t, expected, string(actual))
+}
+
+func TestMarshalAttempts(t *testing.T) {
+	attempts := []Attempt{
+		{Status: StatusFailed, Message: "first"},
+		{Status: StatusError, Message: "second", Rerun: true},
+	}
+
+	tests := []struct {
+		title    string
+		test     Test
+		expected string
+	}{
+		{
+			title:    "agreeing",
+			test:     Test{Name: "test", Status: StatusFailed, Message: "first", Error: Error{Message: "first"}, Attempts: attempts},
+			expected: `<failure message="first"></failure>` + "\n\t\t\t" + `<rerunError message="second"></rerunError>`,
+		},
+		{
+			title:    "changed status",
+			test:     Test{Name: "test", Status: StatusError, Error: Error{Message: "changed"}, Attempts: attempts},
+			expected: `<error message="changed"></error>` + "\n\t\t\t" + `<rerunError message="second"></rerunError>`,
+		},
+		{
+			title:    "flaky",
+			test:     Test{Name: "test", Status: StatusPassed, Attempts: attempts},
+			expected: `<flakyError message="second"></flakyError>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			data, err := Marshal([]Suite{{Name: "suite", Tests: []Test{test.test}}})
+			assertNoError(t, err)
+
+			if !bytes.Contains(data, []byte(test.expected)) {
+				t.Fatalf("output did not contain %q:\n%s", test.expected, data)
+			}
+
+			suites, err := Ingest(data)
+			assertNoError(t, err)
+			assertEqual(t, test.test.Status, suites[0].Tests[0].Status)
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		input    time.Duration
+		expected string
+	}{
+		{0, "0.000"},
+		{time.Second, "1.000"},
+		{5917 * time.Microsecond, "0.005917"},
+		{1234560 * time.Millisecond, "1234.560"},
+		{time.Nanosecond, "0.000000001"},
+	}
+
+	for _, test := range tests {
+		actual := formatDuration(test.input)
+		assertEqual(t, test.expected, actual)
+		assertEqual(t, test.input, duration(actual))
+	}
+}
+
+func TestEncodeDialects(t *testing.T) {
+	suites := []Suite{
+		{
+			Name:    "outer",
+			Package: "example",
+			Suites: []Suite{
+				{
+					Name:     "inner",
+					Hostname: "ci-runner",
+					Properties: map[string]string{
+						"go.version": "1.12",
+					},
+					Tests: []Test{
+						{
+							Name:      "skipped",
+							Classname: "Example",
+							Status:    StatusSkipped,
+							Properties: map[string]string{
+								"file": "example_test.go",
+							},
+							SystemOut: "output",
+						},
+						{
+							Name:   "failed",
+							Status: StatusFailed,
+							Error:  Error{Body: "boom"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{
+			dialect: DialectJenkins(),
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="2" failures="1" errors="0" skipped="1" time="0.000">
+	<testsuite name="inner" package="example" timestamp="2013-05-24T10:23:58" hostname="ci-runner" tests="2" failures="1" errors="0" skipped="1" time="0.000">
+		<properties>
+			<property name="go.version" value="1.12"></property>
+		</properties>
+		<testcase name="skipped" classname="Example">
+			<properties>
+				<property name="file" value="example_test.go"></property>
+			</properties>
+			<skipped></skipped>
+			<system-out>output</system-out>
+		</testcase>
+		<testcase name="failed">
+			<failure>boom</failure>
+		</testcase>
+	</testsuite>
+</testsuites>
+`,
+		},
+		{
+			dialect: DialectGitLab(),
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="2" failures="1" errors="0" skipped="1" time="0.000">
+	<testsuite name="inner" package="example" hostname="ci-runner" tests="2" failures="1" errors="0" skipped="1" time="0.000">
+		<properties>
+			<property name="go.version" value="1.12"></property>
+		</properties>
+		<testcase name="skipped" classname="Example" file="example_test.go">
+			<skipped></skipped>
+			<system-out>output</system-out>
+		</testcase>
+		<testcase name="failed">
+			<failure>boom</failure>
+		</testcase>
+	</testsuite>
+</testsuites>
+`,
+		},
+		{
+			dialect: DialectXSD(),
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+	<testsuite name="inner" package="example" id="0" timestamp="2013-05-24T10:23:58" hostname="ci-runner" tests="2" failures="1" errors="0" time="0.000">
+		<properties>
+			<property name="go.version" value="1.12"></property>
+		</properties>
+		<testcase name="skipped" classname="Example" time="0.000"></testcase>
+		<testcase name="failed" classname="" time="0.000">
+			<failure message="" type="">boom</failure>
+		</testcase>
+		<system-out></system-out>
+		<system-err></system-err>
+	</testsuite>
+</testsuites>
+`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.dialect.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			enc := NewEncoder(&buf)
+			enc.SetDialect(test.dialect)
+			enc.now = func() time.Time {
+				return time.Date(2013, 5, 24, 10, 23, 58, 0, time.UTC)
+			}
+
+			assertNoError(t, enc.Encode(suites))
+			assertEqual(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestLookupDialect(t *testing.T) {
+	dialect, found := LookupDialect("surefire")
+	assertEqual(t, true, found)
+	assertEqual(t, DialectSurefire(), dialect)
+
+	_, found = LookupDialect("unknown")
+	assertEqual(t, false, found)
+}
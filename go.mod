@@ -1,3 +1,5 @@
 module github.com/joshdk/go-junit
 
 go 1.12
+
+require gopkg.in/yaml.v2 v2.4.0
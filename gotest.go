@@ -0,0 +1,361 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// IngestGoTest will parse the given "go test -json" output and return a slice
+// of test suite definitions, with one suite per package.
+//
+// Each test, including each subtest, is recorded as a test of its package
+// suite, named by its full path such as "TestParent/child". Output written by
+// a test is recorded as its SystemOut, without the "=== RUN" and "--- PASS"
+// framing lines, and output written by a package outside of any test is
+// recorded as the SystemOut of its suite, without the "PASS" and "ok" summary
+// lines. Tests that are run more than once, such as with the -count flag,
+// retain the status of their final execution, and record every failed
+// execution as an attempt.
+//
+// Packages that failed to build, or that failed without any of their tests
+// failing, are recorded with an additional erroneous test named after the
+// failure, such as "[build failed]". Tests that never completed, such as
+// after a panic, are also recorded as erroneous.
+func IngestGoTest(data []byte, opts ...Option) ([]Suite, error) {
+	return IngestGoTestReader(bytes.NewReader(data), opts...)
+}
+
+// IngestGoTestFile will parse the given "go test -json" output file and return
+// a slice of test suite definitions.
+func IngestGoTestFile(filename string, opts ...Option) ([]Suite, error) {
+	file, err := os.Open(filename) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint
+
+	return IngestGoTestReader(file, opts...)
+}
+
+// IngestGoTestReader will parse the given "go test -json" output reader and
+// return a slice of test suite definitions. Lines that are not valid JSON
+// objects, such as build errors written to stderr, are ignored.
+func IngestGoTestReader(reader io.Reader, opts ...Option) ([]Suite, error) {
+	var (
+		packages []*goTestPackage
+		indices  = make(map[string]int)
+
+		// builds holds the output of each failed build, by import path.
+		builds = make(map[string]string)
+	)
+
+	lines := bufio.NewReader(reader)
+
+	for {
+		line, err := lines.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if line = bytes.TrimSpace(line); bytes.HasPrefix(line, []byte("{")) {
+			var event goTestEvent
+
+			switch {
+			case json.Unmarshal(line, &event) != nil:
+				// Malformed lines are ignored like any other non-JSON line.
+			case event.Action == "build-output":
+				builds[event.ImportPath] += event.Output
+			case event.Package != "":
+				index, found := indices[event.Package]
+				if !found {
+					index = len(packages)
+					indices[event.Package] = index
+					packages = append(packages, newGoTestPackage(event))
+				}
+
+				packages[index].record(event)
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	suites := make([]Suite, len(packages))
+	for index, pkg := range packages {
+		suites[index] = pkg.finish(builds)
+	}
+
+	return newOptions(opts).finish(suites)
+}
+
+// goTestEvent is a single event written by "go test -json". See "go doc
+// test2json" for a description of each field.
+type goTestEvent struct {
+	Time        time.Time
+	Action      string
+	Package     string
+	Test        string
+	Elapsed     float64
+	Output      string
+	OutputType  string
+	ImportPath  string
+	FailedBuild string
+}
+
+// goTestPackage tracks the events of a single package, as they are read.
+type goTestPackage struct {
+	suite Suite
+
+	// indices is the index of each test within the suite, by name.
+	indices map[string]int
+
+	// runs is the number of times that each test has been started, by name.
+	runs map[string]int
+
+	// output is the output of the current execution of each test, by name.
+	output map[string]string
+
+	// frames is the framing output of the package, such as its final "ok" or
+	// "FAIL" summary, which is excluded from the suite output.
+	frames string
+
+	// typed reports if output events have been seen with an OutputType, in
+	// which case framing lines are identified by that type alone.
+	typed bool
+
+	// failed reports if the package as a whole failed, and failedBuild is
+	// the import path of the package build that caused that failure, if any.
+	failed      bool
+	failedBuild string
+}
+
+func newGoTestPackage(event goTestEvent) *goTestPackage {
+	return &goTestPackage{
+		suite: Suite{
+			Name:      event.Package,
+			Timestamp: event.Time,
+		},
+		indices: make(map[string]int),
+		runs:    make(map[string]int),
+		output:  make(map[string]string),
+	}
+}
+
+// goTestFrames are the prefixes of the lines written by the testing package
+// to mark the start and end of each test, which are followed by the name of
+// that test.
+var goTestFrames = []string{ //nolint:gochecknoglobals
+	"=== RUN ",
+	"=== PAUSE ",
+	"=== CONT ",
+	"=== NAME ",
+	"--- PASS: ",
+	"--- FAIL: ",
+	"--- SKIP: ",
+	"--- BENCH: ",
+}
+
+// record updates the package with the given event.
+func (pkg *goTestPackage) record(event goTestEvent) {
+	if event.OutputType != "" {
+		pkg.typed = true
+	}
+
+	if event.Test == "" {
+		switch {
+		case event.Action == "output" && pkg.frame(event):
+			pkg.frames += event.Output
+		case event.Action == "output":
+			pkg.suite.SystemOut += event.Output
+		case event.Action == "fail":
+			pkg.failed = true
+			pkg.failedBuild = event.FailedBuild
+		}
+
+		return
+	}
+
+	test := pkg.test(event.Test)
+
+	switch event.Action {
+	case "run":
+		pkg.runs[event.Test]++
+		pkg.output[event.Test] = ""
+		test.Status = ""
+
+	case "output":
+		if pkg.frame(event) {
+			return
+		}
+
+		// Test logs are indented by four spaces for each level of nesting.
+		indent := strings.Repeat("    ", strings.Count(event.Test, "/")+1)
+		pkg.output[event.Test] += strings.TrimPrefix(event.Output, indent)
+
+	case "pass":
+		pkg.complete(test, StatusPassed, event.Elapsed)
+	case "fail":
+		pkg.complete(test, StatusFailed, event.Elapsed)
+	case "skip":
+		pkg.complete(test, StatusSkipped, event.Elapsed)
+	}
+}
+
+// frame reports if the given output event is a framing line written by the
+// testing package or by "go test", rather than output written by a test.
+//
+// Newer versions of test2json mark framing lines with an OutputType, but for
+// older versions only the lines that frame the test of the event are
+// recognized, so that similar output written by the test itself is kept.
+func (pkg *goTestPackage) frame(event goTestEvent) bool {
+	if event.OutputType == "frame" {
+		return true
+	}
+
+	line := strings.TrimSuffix(event.Output, "\n")
+
+	if event.Test == "" {
+		// The summary lines of the package are never marked as framing.
+		for _, prefix := range []string{"ok  \t", "?   \t", "FAIL\t"} {
+			if rest := strings.TrimPrefix(line, prefix+pkg.suite.Name); rest != line && (rest == "" || rest[0] == '\t' || rest[0] == ' ') {
+				return true
+			}
+		}
+
+		return !pkg.typed && (line == "PASS" || line == "FAIL")
+	}
+
+	if pkg.typed {
+		return false
+	}
+
+	line = strings.TrimLeft(line, " ")
+
+	for _, prefix := range goTestFrames {
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+
+		name := strings.TrimLeft(line[len(prefix):], " ")
+		if name == event.Test || strings.HasPrefix(name, event.Test+" (") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// test returns the test with the given name, adding it to the suite if
+// needed.
+func (pkg *goTestPackage) test(name string) *Test {
+	index, found := pkg.indices[name]
+	if !found {
+		index = len(pkg.suite.Tests)
+		pkg.indices[name] = index
+		pkg.suite.Tests = append(pkg.suite.Tests, Test{
+			Name:      name,
+			Classname: path.Base(pkg.suite.Name),
+		})
+	}
+
+	return &pkg.suite.Tests[index]
+}
+
+// complete records the result of the current execution of the given test.
+func (pkg *goTestPackage) complete(test *Test, status Status, elapsed float64) {
+	output := pkg.output[test.Name]
+
+	test.Status = status
+	test.SystemOut = output
+	test.Message = ""
+	test.Error = nil
+
+	// Elapsed times are given in fractional seconds, and are rounded to
+	// avoid floating point errors.
+	test.Duration = time.Duration(math.Round(elapsed*1e6)) * time.Microsecond
+
+	switch status {
+	case StatusSkipped:
+		test.Message = strings.TrimSpace(output)
+	case StatusFailed:
+		pkg.fail(test, status, "Failed", strings.TrimRight(output, "\n"))
+	}
+}
+
+// fail records the given test as having failed or errored, with the given
+// message and details.
+func (pkg *goTestPackage) fail(test *Test, status Status, message, body string) {
+	test.Status = status
+	test.Message = message
+	test.Error = Error{
+		Message: message,
+		Body:    body,
+	}
+	test.Attempts = append(test.Attempts, Attempt{
+		Status:   status,
+		Rerun:    pkg.runs[test.Name] > 1,
+		Message:  message,
+		Body:     body,
+		Duration: test.Duration,
+	})
+}
+
+// finish returns the suite for the package, after all events have been
+// recorded. The given build output is used to describe build failures.
+func (pkg *goTestPackage) finish(builds map[string]string) Suite {
+	failures := 0
+
+	for index := range pkg.suite.Tests {
+		test := &pkg.suite.Tests[index]
+
+		if test.Status == "" {
+			test.SystemOut = pkg.output[test.Name]
+			pkg.fail(test, StatusError, "Did not complete", strings.TrimRight(test.SystemOut, "\n"))
+		}
+
+		// Any failed execution explains a failure of the package, even if
+		// the test passed when it was rerun.
+		if len(test.Attempts) > 0 {
+			failures++
+		}
+	}
+
+	var name string
+
+	switch {
+	case pkg.failedBuild != "" || strings.Contains(pkg.frames, "[build failed]"):
+		name = "[build failed]"
+	case strings.Contains(pkg.frames, "[setup failed]"):
+		name = "[setup failed]"
+	case pkg.failed && failures == 0:
+		name = "[package failed]"
+	}
+
+	if name != "" {
+		body := builds[pkg.failedBuild]
+		if body == "" {
+			body = pkg.suite.SystemOut + pkg.frames
+		}
+
+		pkg.runs[name]++
+		pkg.fail(pkg.test(name), StatusError, "Failed", strings.TrimRight(body, "\n"))
+	}
+
+	pkg.suite.Aggregate()
+
+	return pkg.suite
+}
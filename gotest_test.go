@@ -0,0 +1,192 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIngestGoTestFile(t *testing.T) {
+	suites, err := IngestGoTestFile("testdata/go-test.json")
+	assertNoError(t, err)
+	assertLen(t, suites, 4)
+
+	broken := suites[0]
+	assertEqual(t, "package/broken", broken.Name)
+	assertLen(t, broken.Tests, 1)
+	assertEqual(t, "[build failed]", broken.Tests[0].Name)
+	assertEqual(t, "broken", broken.Tests[0].Classname)
+	assertEqual(t, StatusError, broken.Tests[0].Status)
+	assertError(t, broken.Tests[0].Error, "# package/broken [package/broken.test]\nbroken/a_test.go:5:33: undefined: undefined")
+	assertEqual(t, Totals{Tests: 1, Error: 1}, broken.Totals)
+
+	empty := suites[1]
+	assertEqual(t, "package/empty", empty.Name)
+	assertLen(t, empty.Tests, 0)
+	assertEqual(t, "", empty.SystemOut)
+
+	name1 := suites[2]
+	assertEqual(t, "package/name1", name1.Name)
+	assertEqual(t, time.Date(2026, 10, 16, 22, 39, 36, 627358515, time.UTC), name1.Timestamp)
+	assertLen(t, name1.Tests, 4)
+	assertEqual(t, "TestOne", name1.Tests[0].Name)
+	assertEqual(t, "name1", name1.Tests[0].Classname)
+	assertEqual(t, "TestTwo", name1.Tests[1].Name)
+	assertEqual(t, "TestTwo/first", name1.Tests[2].Name)
+	assertEqual(t, "TestTwo/second", name1.Tests[3].Name)
+	assertEqual(t, StatusSkipped, name1.Tests[3].Status)
+	assertEqual(t, "a_test.go:9: not ready", name1.Tests[3].Message)
+	assertEqual(t, "", name1.SystemOut)
+	assertEqual(t, Totals{Tests: 4, Passed: 3, Skipped: 1}, name1.Totals)
+
+	name2 := suites[3]
+	assertLen(t, name2.Tests, 2)
+	assertEqual(t, StatusFailed, name2.Tests[0].Status)
+	assertEqual(t, "Failed", name2.Tests[0].Message)
+	assertEqual(t, "some output\na_test.go:10: Error message\na_test.go:11: Longer\n    \terror\n    \tmessage.\n", name2.Tests[0].SystemOut)
+	assertError(t, name2.Tests[0].Error, "some output\na_test.go:10: Error message\na_test.go:11: Longer\n    \terror\n    \tmessage.")
+	assertLen(t, name2.Tests[0].Attempts, 1)
+	assertEqual(t, StatusSkipped, name2.Tests[1].Status)
+	assertEqual(t, "a_test.go:14: Skip message", name2.Tests[1].Message)
+	assertEqual(t, Totals{Tests: 2, Failed: 1, Skipped: 1}, name2.Totals)
+}
+
+func TestIngestGoTest(t *testing.T) {
+	tests := []struct {
+		title string
+		input string
+		check func(*testing.T, []Suite)
+	}{
+		{
+			title: "durations",
+			input: `
+{"Action":"run","Package":"pkg","Test":"TestA"}
+{"Action":"pass","Package":"pkg","Test":"TestA","Elapsed":0.57}
+{"Action":"pass","Package":"pkg","Elapsed":0.6}
+`,
+			check: func(t *testing.T, suites []Suite) {
+				assertLen(t, suites, 1)
+				assertEqual(t, 570*time.Millisecond, suites[0].Tests[0].Duration)
+				assertEqual(t, Totals{Tests: 1, Passed: 1, Duration: 570 * time.Millisecond}, suites[0].Totals)
+			},
+		},
+		{
+			title: "rerun",
+			input: `
+{"Action":"run","Package":"pkg","Test":"TestA"}
+{"Action":"output","Package":"pkg","Test":"TestA","Output":"    a_test.go:1: boom\n"}
+{"Action":"fail","Package":"pkg","Test":"TestA"}
+{"Action":"run","Package":"pkg","Test":"TestA"}
+{"Action":"pass","Package":"pkg","Test":"TestA"}
+{"Action":"fail","Package":"pkg"}
+`,
+			check: func(t *testing.T, suites []Suite) {
+				assertLen(t, suites[0].Tests, 1)
+				assertEqual(t, StatusPassed, suites[0].Tests[0].Status)
+				assertEqual(t, nil, suites[0].Tests[0].Error)
+				assertEqual(t, true, suites[0].Tests[0].Flaky())
+				assertEqual(t, []Attempt{{Status: StatusFailed, Message: "Failed", Body: "a_test.go:1: boom"}}, suites[0].Tests[0].Attempts)
+			},
+		},
+		{
+			title: "incomplete",
+			input: `
+{"Action":"run","Package":"pkg","Test":"TestA"}
+{"Action":"output","Package":"pkg","Test":"TestA","Output":"panic: boom\n"}
+`,
+			check: func(t *testing.T, suites []Suite) {
+				assertLen(t, suites[0].Tests, 1)
+				assertEqual(t, StatusError, suites[0].Tests[0].Status)
+				assertEqual(t, "Did not complete", suites[0].Tests[0].Message)
+				assertError(t, suites[0].Tests[0].Error, "panic: boom")
+			},
+		},
+		{
+			title: "package failed",
+			input: `
+{"Action":"run","Package":"pkg","Test":"TestA"}
+{"Action":"pass","Package":"pkg","Test":"TestA"}
+{"Action":"output","Package":"pkg","Output":"exit status 1\n"}
+{"Action":"fail","Package":"pkg"}
+`,
+			check: func(t *testing.T, suites []Suite) {
+				assertLen(t, suites[0].Tests, 2)
+				assertEqual(t, "[package failed]", suites[0].Tests[1].Name)
+				assertEqual(t, StatusError, suites[0].Tests[1].Status)
+				assertError(t, suites[0].Tests[1].Error, "exit status 1")
+			},
+		},
+		{
+			title: "framing lines",
+			input: `
+{"Action":"run","Package":"pkg","Test":"TestA"}
+{"Action":"output","Package":"pkg","Test":"TestA","Output":"=== RUN   TestA\n","OutputType":"frame"}
+{"Action":"output","Package":"pkg","Test":"TestA","Output":"=== RUN   TestB\n"}
+{"Action":"output","Package":"pkg","Test":"TestA","Output":"--- PASS: TestA (0.00s)\n","OutputType":"frame"}
+{"Action":"pass","Package":"pkg","Test":"TestA"}
+{"Action":"output","Package":"pkg","Output":"PASS\n","OutputType":"frame"}
+{"Action":"output","Package":"pkg","Output":"ok  \tpkg\t0.002s\n"}
+{"Action":"pass","Package":"pkg"}
+`,
+			check: func(t *testing.T, suites []Suite) {
+				assertEqual(t, "=== RUN   TestB\n", suites[0].Tests[0].SystemOut)
+				assertEqual(t, "", suites[0].SystemOut)
+			},
+		},
+		{
+			title: "legacy framing lines",
+			input: `
+{"Action":"run","Package":"pkg","Test":"TestA"}
+{"Action":"output","Package":"pkg","Test":"TestA","Output":"=== RUN   TestA\n"}
+{"Action":"output","Package":"pkg","Test":"TestA","Output":"=== RUN   TestB\n"}
+{"Action":"output","Package":"pkg","Test":"TestA","Output":"--- PASS: TestA (0.00s)\n"}
+{"Action":"pass","Package":"pkg","Test":"TestA"}
+{"Action":"output","Package":"pkg","Output":"PASS\n"}
+{"Action":"output","Package":"pkg","Output":"coverage: 50.0% of statements\n"}
+{"Action":"output","Package":"pkg","Output":"ok  \tpkg\t0.002s\n"}
+{"Action":"pass","Package":"pkg"}
+`,
+			check: func(t *testing.T, suites []Suite) {
+				assertEqual(t, "=== RUN   TestB\n", suites[0].Tests[0].SystemOut)
+				assertEqual(t, "coverage: 50.0% of statements\n", suites[0].SystemOut)
+			},
+		},
+		{
+			title: "legacy build failure",
+			input: `
+# pkg
+./a_test.go:1:1: syntax error
+{"Action":"output","Package":"pkg","Output":"FAIL\tpkg [build failed]\n"}
+{"Action":"fail","Package":"pkg"}
+`,
+			check: func(t *testing.T, suites []Suite) {
+				assertLen(t, suites, 1)
+				assertLen(t, suites[0].Tests, 1)
+				assertEqual(t, "[build failed]", suites[0].Tests[0].Name)
+				assertError(t, suites[0].Tests[0].Error, "FAIL\tpkg [build failed]")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			suites, err := IngestGoTest([]byte(test.input))
+			assertNoError(t, err)
+			test.check(t, suites)
+		})
+	}
+}
+
+func TestIngestGoTestMalformed(t *testing.T) {
+	suites, err := IngestGoTest([]byte(`{"Action":"run","Package":"pkg","Test":"TestA"}
+{"Action":
+{"Action":"pass","Package":"pkg","Test":"TestA"}
+{"Action":`))
+	assertNoError(t, err)
+	assertLen(t, suites, 1)
+	assertEqual(t, Totals{Tests: 1, Passed: 1}, suites[0].Totals)
+}
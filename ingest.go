@@ -6,52 +6,202 @@
 package junit
 
 import (
+	"encoding/xml"
+	"io"
 	"strconv"
 	"strings"
 	"time"
 )
 
-// findSuites performs a depth-first search through the XML document, and
-// attempts to ingest any "testsuite" tags that are encountered.
-func findSuites(nodes []xmlNode, suites chan Suite) {
-	for _, node := range nodes {
-		switch node.XMLName.Local {
-		case "testsuite":
-			suites <- ingestSuite(node)
-		default:
-			findSuites(node.Nodes, suites)
+// decoder wraps an XML token stream, and tracks the state needed to ingest
+// suites from that stream incrementally.
+type decoder struct {
+	*xml.Decoder
+
+	// parents is the chain of suites that are currently being ingested,
+	// ordered from outermost to innermost.
+	parents []*Suite
+
+	// tests, if set, is called with each testcase as soon as it has been
+	// read. Such testcases (and nested suites) are then not retained by their
+	// parent suite.
+	tests func(parents []Suite, test Test) error
+
+	// strict causes suites with inconsistent declared counts to fail
+	// ingestion.
+	strict bool
+}
+
+func newDecoder(reader io.Reader, config options) *decoder {
+	dec := xml.NewDecoder(reparentXML(reader))
+	dec.CharsetReader = config.charsetReader
+
+	return &decoder{
+		Decoder: dec,
+		strict:  config.strict,
+	}
+}
+
+// findSuites performs a depth-first search through the XML token stream, and
+// attempts to ingest any "testsuite" tags that are encountered. Each top-level
+// suite is passed to the given function as soon as its closing tag is read.
+func findSuites(dec *decoder, fn func(Suite) error) error {
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "testsuite" {
+			continue
+		}
+
+		suite, err := ingestSuite(dec, start)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(suite); err != nil {
+			return err
 		}
 	}
 }
 
-func ingestSuite(root xmlNode) Suite {
+// ingestSuite consumes tokens up to and including the end of the given
+// "testsuite" start tag. Only the children of a single testcase, properties
+// or output node are ever decoded into memory at once.
+func ingestSuite(dec *decoder, start xml.StartElement) (Suite, error) {
+	attrs := attrMap(start.Attr)
 	suite := Suite{
-		Name:       root.Attr("name"),
-		Package:    root.Attr("package"),
-		Properties: root.Attrs,
+		Name:       attrs["name"],
+		Package:    attrs["package"],
+		ID:         attrs["id"],
+		Hostname:   attrs["hostname"],
+		Timestamp:  timestamp(attrs["timestamp"]),
+		Declared:   declaredTotals(attrs),
+		Attributes: attrs,
 	}
 
-	for _, node := range root.Nodes {
-		switch node.XMLName.Local {
-		case "testsuite":
-			testsuite := ingestSuite(node)
-			suite.Suites = append(suite.Suites, testsuite)
-		case "testcase":
-			testcase := ingestTestcase(node)
-			suite.Tests = append(suite.Tests, testcase)
-		case "properties":
-			props := ingestProperties(node)
+	dec.parents = append(dec.parents, &suite)
+	defer func() {
+		dec.parents = dec.parents[:len(dec.parents)-1]
+	}()
+
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return Suite{}, err
+		}
+
+		switch token := token.(type) {
+		case xml.EndElement:
+			// When tests are not retained, the totals have instead been
+			// tallied as each test was read.
+			if dec.tests == nil {
+				suite.Aggregate()
+			}
+
+			if dec.strict {
+				if err := suite.Validate(); err != nil {
+					return Suite{}, err
+				}
+			}
+
+			return suite, nil
+
+		case xml.StartElement:
+			if err := ingestSuiteNode(dec, token, &suite); err != nil {
+				return Suite{}, err
+			}
+		}
+	}
+}
+
+// ingestSuiteNode consumes a single child node of a "testsuite" tag, and
+// records it in the given suite. Unrecognized nodes are skipped entirely.
+func ingestSuiteNode(dec *decoder, start xml.StartElement, suite *Suite) error {
+	switch start.Name.Local {
+	case "testsuite":
+		testsuite, err := ingestSuite(dec, start)
+		if err != nil {
+			return err
+		}
+		if dec.tests != nil {
+			suite.Totals = suite.Totals.add(testsuite.Totals)
+
+			return nil
+		}
+		suite.Suites = append(suite.Suites, testsuite)
+
+		return nil
+	case "testcase", "properties", "system-out", "system-err":
+	default:
+		return dec.Skip()
+	}
+
+	var node xmlNode
+	if err := dec.DecodeElement(&node, &start); err != nil {
+		return err
+	}
+
+	switch node.XMLName.Local {
+	case "testcase":
+		testcase := ingestTestcase(node)
+		if dec.tests != nil {
+			suite.Totals = suite.Totals.add(testcase.totals())
+
+			return dec.tests(dec.ancestors(), testcase)
+		}
+		suite.Tests = append(suite.Tests, testcase)
+	case "properties":
+		props := ingestProperties(node)
+		if suite.Properties == nil {
 			suite.Properties = props
-		case "system-out":
-			suite.SystemOut = string(node.Content)
-		case "system-err":
-			suite.SystemErr = string(node.Content)
+
+			break
+		}
+		for name, value := range props {
+			suite.Properties[name] = value
 		}
+	case "system-out":
+		suite.SystemOut = string(node.Content)
+	case "system-err":
+		suite.SystemErr = string(node.Content)
 	}
 
-	suite.Aggregate()
+	return nil
+}
+
+// ancestors returns a copy of the chain of suites that are currently being
+// ingested, ordered from outermost to innermost. The maps of each suite are
+// copied too, since they may still be modified as ingestion continues.
+func (dec *decoder) ancestors() []Suite {
+	parents := make([]Suite, len(dec.parents))
+	for index, parent := range dec.parents {
+		parents[index] = *parent
+		parents[index].Attributes = copyStrings(parent.Attributes)
+		parents[index].Properties = copyStrings(parent.Properties)
+	}
+
+	return parents
+}
+
+// copyStrings returns a copy of the given map, or nil if it is nil.
+func copyStrings(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+
+	copied := make(map[string]string, len(values))
+	for name, value := range values {
+		copied[name] = value
+	}
 
-	return suite
+	return copied
 }
 
 func ingestProperties(root xmlNode) map[string]string {
@@ -86,10 +236,16 @@ func ingestTestcase(root xmlNode) Test {
 			test.Status = StatusFailed
 			test.Message = node.Attr("message")
 			test.Error = ingestError(node)
+			test.Attempts = append(test.Attempts, ingestAttempt(node, StatusFailed, false))
 		case "error":
 			test.Status = StatusError
 			test.Message = node.Attr("message")
 			test.Error = ingestError(node)
+			test.Attempts = append(test.Attempts, ingestAttempt(node, StatusError, false))
+		case "rerunFailure", "flakyFailure":
+			test.Attempts = append(test.Attempts, ingestAttempt(node, StatusFailed, true))
+		case "rerunError", "flakyError":
+			test.Attempts = append(test.Attempts, ingestAttempt(node, StatusError, true))
 		case "system-out":
 			test.SystemOut = string(node.Content)
 		case "system-err":
@@ -108,6 +264,101 @@ func ingestError(root xmlNode) Error {
 	}
 }
 
+// ingestAttempt ingests a single "failure" or "error" tag, or one of the
+// equivalent Surefire rerun tags. Rerun tags contain their details as nested
+// tags, rather than as text.
+func ingestAttempt(root xmlNode, status Status, rerun bool) Attempt {
+	attempt := Attempt{
+		Status:   status,
+		Rerun:    rerun,
+		Message:  root.Attr("message"),
+		Type:     root.Attr("type"),
+		Duration: duration(root.Attr("time")),
+	}
+
+	if len(root.Nodes) == 0 {
+		attempt.Body = string(root.Content)
+	}
+
+	for _, node := range root.Nodes {
+		switch node.XMLName.Local {
+		case "stackTrace":
+			attempt.StackTrace = string(node.Content)
+		case "system-out":
+			attempt.SystemOut = string(node.Content)
+		case "system-err":
+			attempt.SystemErr = string(node.Content)
+		}
+	}
+
+	return attempt
+}
+
+// declaredAttrs are the suite attributes which declare counts and times.
+var declaredAttrs = []string{"tests", "failures", "errors", "skipped", "skips", "time"} //nolint:gochecknoglobals
+
+// declaredTotals returns the counts and time declared by the given suite
+// attributes. Absent or malformed values are treated as zero.
+func declaredTotals(attrs map[string]string) Totals {
+	count := func(names ...string) int {
+		for _, name := range names {
+			if value, err := strconv.Atoi(strings.TrimSpace(attrs[name])); err == nil {
+				return value
+			}
+		}
+
+		return 0
+	}
+
+	totals := Totals{
+		Tests:    count("tests"),
+		Skipped:  count("skipped", "skips"),
+		Failed:   count("failures"),
+		Error:    count("errors"),
+		Duration: duration(attrs["time"]),
+	}
+
+	if passed := totals.Tests - totals.Skipped - totals.Failed - totals.Error; passed > 0 {
+		totals.Passed = passed
+	}
+
+	return totals
+}
+
+// timestampLayouts are the layouts used by various frameworks for suite
+// timestamps, in order of preference. Layouts without a timezone are assumed
+// to be UTC.
+var timestampLayouts = []string{ //nolint:gochecknoglobals
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.UnixDate,
+}
+
+func timestamp(timespec string) time.Time {
+	timespec = strings.TrimSpace(timespec)
+	if timespec == "" {
+		return time.Time{}
+	}
+
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, timespec); err == nil {
+			return t
+		}
+	}
+
+	// Check if there was a valid unix timestamp, in seconds
+	if s, err := strconv.ParseFloat(timespec, 64); err == nil {
+		return time.Unix(0, int64(s*float64(time.Second))).UTC()
+	}
+
+	return time.Time{}
+}
+
 func duration(timespec string) time.Duration {
 	// Remove commas for larger durations
 	timespec = strings.ReplaceAll(timespec, ",", "")
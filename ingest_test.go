@@ -6,7 +6,11 @@
 package junit
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -27,6 +31,11 @@ func TestExamplesInTheWild(t *testing.T) {
 				assertLen(t, suites[0].Tests, 0)
 				assertLen(t, suites[1].Tests, 3)
 				assertError(t, suites[1].Tests[0].Error, "Assertion failed")
+				assertEqual(t, time.Date(2013, 5, 24, 10, 23, 58, 0, time.UTC), suites[1].Timestamp)
+				assertEqual(t, Totals{Tests: 3, Passed: 1, Skipped: 1, Failed: 1, Duration: 6 * time.Millisecond}, suites[1].Declared)
+				assertEqual(t, "on", suites[1].Properties["compiler.debug"])
+				assertEqual(t, "", suites[1].Properties["name"])
+				assertEqual(t, "JUnitXmlReporter.constructor", suites[1].Attributes["name"])
 			},
 		},
 		{
@@ -86,6 +95,9 @@ func TestExamplesInTheWild(t *testing.T) {
 				assertLen(t, suites[0].Tests, 6)
 				assertEqual(t, "\n", suites[0].Properties["line.separator"])
 				assertEqual(t, `\`, suites[0].Properties["file.separator"])
+				assertEqual(t, "1", suites[0].ID)
+				assertEqual(t, "1", suites[0].Attributes["id"])
+				assertEqual(t, 1426*time.Second, suites[0].Declared.Duration)
 			},
 		},
 		{
@@ -129,6 +141,13 @@ func TestExamplesInTheWild(t *testing.T) {
 						Type: "java.lang.AssertionError",
 						Body: "java.lang.AssertionError\n\tat com.example.FooTest.testStdoutStderr(FooTest.java:13)\n",
 					},
+					Attempts: []Attempt{
+						{
+							Status: StatusFailed,
+							Type:   "java.lang.AssertionError",
+							Body:   "java.lang.AssertionError\n\tat com.example.FooTest.testStdoutStderr(FooTest.java:13)\n",
+						},
+					},
 					Properties: map[string]string{
 						"classname": "com.example.FooTest",
 						"name":      "testStdoutStderr",
@@ -141,6 +160,53 @@ func TestExamplesInTheWild(t *testing.T) {
 				assertEqual(t, testcase, suites[0].Tests[0])
 			},
 		},
+		{
+			title:    "surefire rerun example",
+			filename: "testdata/surefire-rerun.xml",
+			check: func(t *testing.T, suites []Suite) {
+				assertLen(t, suites, 1)
+				assertLen(t, suites[0].Tests, 3)
+
+				stable := suites[0].Tests[0]
+				assertEqual(t, StatusPassed, stable.Status)
+				assertEqual(t, false, stable.Flaky())
+
+				flaky := suites[0].Tests[1]
+				assertEqual(t, StatusPassed, flaky.Status)
+				assertEqual(t, true, flaky.Flaky())
+				assertEqual(t, nil, flaky.Error)
+				assertEqual(t, []Attempt{
+					{
+						Status:     StatusFailed,
+						Rerun:      true,
+						Message:    "expected:<1> but was:<2>",
+						Type:       "org.opentest4j.AssertionFailedError",
+						StackTrace: "org.opentest4j.AssertionFailedError: expected: <1> but was: <2>\n\tat com.example.RetryTest.testFlaky(RetryTest.java:21)\n",
+						SystemOut:  "attempt 1\n",
+						Duration:   98 * time.Millisecond,
+					},
+					{
+						Status:     StatusError,
+						Rerun:      true,
+						Message:    "Connection refused",
+						Type:       "java.net.ConnectException",
+						StackTrace: "java.net.ConnectException: Connection refused\n\tat com.example.RetryTest.testFlaky(RetryTest.java:19)\n",
+						SystemErr:  "attempt 2\n",
+						Duration:   51 * time.Millisecond,
+					},
+				}, flaky.Attempts)
+
+				broken := suites[0].Tests[2]
+				assertEqual(t, StatusFailed, broken.Status)
+				assertEqual(t, false, broken.Flaky())
+				assertLen(t, broken.Attempts, 3)
+				assertEqual(t, false, broken.Attempts[0].Rerun)
+				assertEqual(t, broken.Error.(Error).Body, broken.Attempts[0].Body)
+				assertEqual(t, StatusFailed, broken.Attempts[1].Status)
+				assertEqual(t, StatusError, broken.Attempts[2].Status)
+				assertEqual(t, "Timed out", broken.Attempts[2].Message)
+			},
+		},
 		{
 			title:    "fastlane example",
 			filename: "testdata/fastlane-trainer.xml",
@@ -158,6 +224,13 @@ func TestExamplesInTheWild(t *testing.T) {
 						Message: "XCTAssertTrue failed",
 						Body:    "\n            ",
 					},
+					Attempts: []Attempt{
+						{
+							Status:  StatusFailed,
+							Message: "XCTAssertTrue failed",
+							Body:    "\n            ",
+						},
+					},
 					Properties: map[string]string{
 						"classname": "TestClassSample",
 						"name":      "testSomething()",
@@ -183,7 +256,7 @@ func TestExamplesInTheWild(t *testing.T) {
 				assertLen(t, suite.Suites, 2)
 
 				assertEqual(t, "SampleTest", suite.Name)
-				assertEqual(t, "/untitled/tests/SampleTest.php", suite.Properties["file"])
+				assertEqual(t, "/untitled/tests/SampleTest.php", suite.Attributes["file"])
 
 				testcase := Test{
 					Name:      "testA",
@@ -233,3 +306,141 @@ func TestExamplesInTheWild(t *testing.T) {
 		})
 	}
 }
+
+func TestIngestStream(t *testing.T) {
+	t.Run("suites are streamed in order", func(t *testing.T) {
+		input := []byte(`
+			<testsuites>
+				<testsuite name="first">
+					<testcase name="one" />
+					<testsuite name="nested">
+						<testcase name="two" />
+					</testsuite>
+				</testsuite>
+			</testsuites>
+			<testsuite name="second">
+				<unknown><testcase name="ignored" /></unknown>
+				<testcase name="three" />
+			</testsuite>
+		`)
+
+		var names []string
+		err := IngestStream(bytes.NewReader(input), func(suite Suite) error {
+			names = append(names, suite.Name)
+			assertLen(t, suite.Tests, 1)
+
+			return nil
+		})
+
+		assertNoError(t, err)
+		assertEqual(t, []string{"first", "second"}, names)
+	})
+
+	t.Run("callback errors stop ingestion", func(t *testing.T) {
+		input := []byte(`<testsuite name="first" /><testsuite name="second" />`)
+
+		var count int
+		err := IngestStream(bytes.NewReader(input), func(suite Suite) error {
+			count++
+
+			return errors.New("stop")
+		})
+
+		assertError(t, err, "stop")
+		assertEqual(t, 1, count)
+	})
+
+	t.Run("suites before a syntax error are streamed", func(t *testing.T) {
+		input := []byte(`<testsuite name="first" /><testsuite name="second">`)
+
+		var names []string
+		err := IngestStream(bytes.NewReader(input), func(suite Suite) error {
+			names = append(names, suite.Name)
+
+			return nil
+		})
+
+		assertError(t, err, "XML syntax error on line 1: element <testsuite> closed by </fake-root>")
+		assertEqual(t, []string{"first"}, names)
+
+		suites, err := Ingest(input)
+		assertError(t, err, "XML syntax error on line 1: element <testsuite> closed by </fake-root>")
+		assertLen(t, suites, 0)
+	})
+}
+
+func TestIngestTestStream(t *testing.T) {
+	file, err := os.Open("testdata/phpunit.xml")
+	assertNoError(t, err)
+	defer file.Close() //nolint
+
+	var paths []string
+	err = IngestTestStream(file, func(parents []Suite, test Test) error {
+		names := make([]string, 0, len(parents)+1)
+		for _, parent := range parents {
+			assertLen(t, parent.Tests, 0)
+			assertLen(t, parent.Suites, 0)
+			names = append(names, parent.Name)
+		}
+		paths = append(paths, strings.Join(append(names, test.Name), " > "))
+
+		return nil
+	})
+
+	assertNoError(t, err)
+	assertEqual(t, []string{
+		"/untitled/tests > SampleTest > testA",
+		`/untitled/tests > SampleTest > SampleTest::testB > testB with data set "bool"`,
+		`/untitled/tests > SampleTest > SampleTest::testB > testB with data set "int"`,
+		`/untitled/tests > SampleTest > SampleTest::testB > testB with data set "string"`,
+		"/untitled/tests > SampleTest > SampleTest::testC > testC with data set #0",
+		"/untitled/tests > SampleTest > SampleTest::testC > testC with data set #1",
+		"/untitled/tests > SampleTest > SampleTest::testC > testC with data set #2",
+	}, paths)
+}
+
+func TestIngestTestStreamProperties(t *testing.T) {
+	input := `<testsuite name="suite">
+	<properties><property name="first" value="1"/></properties>
+	<testcase name="test"/>
+	<properties><property name="second" value="2"/></properties>
+</testsuite>`
+
+	var received []Suite
+	err := IngestTestStream(strings.NewReader(input), func(parents []Suite, _ Test) error {
+		received = parents
+
+		return nil
+	})
+
+	assertNoError(t, err)
+	assertLen(t, received, 1)
+	assertEqual(t, map[string]string{"first": "1"}, received[0].Properties)
+}
+
+func TestTimestamp(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"", time.Time{}},
+		{"not a time", time.Time{}},
+		{"2013-05-24T10:23:58", time.Date(2013, 5, 24, 10, 23, 58, 0, time.UTC)},
+		{"2013-05-24T10:23:58.123", time.Date(2013, 5, 24, 10, 23, 58, 123000000, time.UTC)},
+		{"2013-05-24T10:23:58Z", time.Date(2013, 5, 24, 10, 23, 58, 0, time.UTC)},
+		{"2013-05-24T10:23:58+02:00", time.Date(2013, 5, 24, 8, 23, 58, 0, time.UTC)},
+		{"2013-05-24T10:23:58+0200", time.Date(2013, 5, 24, 8, 23, 58, 0, time.UTC)},
+		{"2013-05-24 10:23:58", time.Date(2013, 5, 24, 10, 23, 58, 0, time.UTC)},
+		{"Fri, 24 May 2013 10:23:58 +0000", time.Date(2013, 5, 24, 10, 23, 58, 0, time.UTC)},
+		{"1369391038", time.Date(2013, 5, 24, 10, 23, 58, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		actual := timestamp(test.input)
+		if !test.expected.Equal(actual) {
+			t.Fatalf("timestamp %q was not equal: \n"+
+				"expected: %v\n"+
+				"actual  : %v", test.input, test.expected, actual)
+		}
+	}
+}
@@ -12,12 +12,15 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
 )
 
 // IngestDir will search the given directory for XML files and return a slice
-// of all contained JUnit test suite definitions.
-func IngestDir(directory string) ([]Suite, error) {
+// of all contained JUnit test suite definitions. Which files are ingested can
+// be configured using WithFileFilter, and TRX files can also be ingested
+// using WithTRX.
+func IngestDir(directory string, opts ...Option) ([]Suite, error) {
+	config := newOptions(opts)
+
 	var filenames []string
 
 	err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
@@ -25,8 +28,8 @@ func IngestDir(directory string) ([]Suite, error) {
 			return err
 		}
 
-		// Add all regular files that end with ".xml"
-		if info.Mode().IsRegular() && strings.HasSuffix(info.Name(), ".xml") {
+		// Add all regular files that pass the configured filter
+		if info.Mode().IsRegular() && config.filter(path) {
 			filenames = append(filenames, path)
 		}
 
@@ -36,16 +39,16 @@ func IngestDir(directory string) ([]Suite, error) {
 		return nil, err
 	}
 
-	return IngestFiles(filenames)
+	return IngestFiles(filenames, opts...)
 }
 
 // IngestFiles will parse the given XML files and return a slice of all
 // contained JUnit test suite definitions.
-func IngestFiles(filenames []string) ([]Suite, error) {
+func IngestFiles(filenames []string, opts ...Option) ([]Suite, error) {
 	all := make([]Suite, 0)
 
 	for _, filename := range filenames {
-		suites, err := IngestFile(filename)
+		suites, err := IngestFile(filename, opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -56,44 +59,74 @@ func IngestFiles(filenames []string) ([]Suite, error) {
 }
 
 // IngestFile will parse the given XML file and return a slice of all contained
-// JUnit test suite definitions.
-func IngestFile(filename string) ([]Suite, error) {
+// JUnit test suite definitions. If WithTRX is given, files ending with ".trx"
+// are instead parsed as Visual Studio TRX files.
+func IngestFile(filename string, opts ...Option) ([]Suite, error) {
+	if newOptions(opts).trx && isTRX(filename) {
+		return IngestTRXFile(filename, opts...)
+	}
+
 	file, err := os.Open(filename) //nolint:gosec
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close() //nolint
 
-	return IngestReader(file)
+	return IngestReader(file, opts...)
 }
 
 // IngestReader will parse the given XML reader and return a slice of all
 // contained JUnit test suite definitions.
-func IngestReader(reader io.Reader) ([]Suite, error) {
-	var (
-		suiteChan = make(chan Suite)
-		suites    = make([]Suite, 0)
-	)
+func IngestReader(reader io.Reader, opts ...Option) ([]Suite, error) {
+	suites := make([]Suite, 0)
 
-	nodes, err := parse(reader)
+	err := IngestStream(reader, func(suite Suite) error {
+		suites = append(suites, suite)
+
+		return nil
+	}, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	go func() {
-		findSuites(nodes, suiteChan)
-		close(suiteChan)
-	}()
+	return suites, nil
+}
 
-	for suite := range suiteChan {
-		suites = append(suites, suite)
-	}
+// IngestStream will parse the given XML reader and call the given function
+// with each contained top-level JUnit test suite definition, as soon as that
+// suite has been read. Only a single top-level suite is held in memory at a
+// time, making this suitable for very large reports.
+//
+// Ingestion stops at the first parsing error, or at the first error returned
+// by the given function, and that error is returned.
+func IngestStream(reader io.Reader, fn func(Suite) error, opts ...Option) error {
+	return findSuites(newDecoder(reader, newOptions(opts)), fn)
+}
 
-	return suites, nil
+// IngestTestStream will parse the given XML reader and call the given function
+// with each contained JUnit test definition, as soon as that test has been
+// read. Neither tests nor suites are retained, making this suitable for
+// reports containing very large suites.
+//
+// Each test is accompanied by the chain of suites that it is nested within,
+// ordered from outermost to innermost. These suites carry only their name,
+// package, properties, and any output that preceded the test. Their tests and
+// nested suites are not populated, and their totals only account for the
+// tests that have been read so far.
+//
+// Ingestion stops at the first parsing error, or at the first error returned
+// by the given function, and that error is returned.
+func IngestTestStream(reader io.Reader, fn func(parents []Suite, test Test) error, opts ...Option) error {
+	dec := newDecoder(reader, newOptions(opts))
+	dec.tests = fn
+
+	return findSuites(dec, func(Suite) error {
+		return nil
+	})
 }
 
 // Ingest will parse the given XML data and return a slice of all contained
 // JUnit test suite definitions.
-func Ingest(data []byte) ([]Suite, error) {
-	return IngestReader(bytes.NewReader(data))
+func Ingest(data []byte, opts ...Option) ([]Suite, error) {
+	return IngestReader(bytes.NewReader(data), opts...)
 }
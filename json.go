@@ -0,0 +1,80 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// IngestJSON will parse the given JSON data, as produced by MarshalJSON, and
+// return a slice of test suite definitions.
+func IngestJSON(data []byte, opts ...Option) ([]Suite, error) {
+	return IngestJSONReader(bytes.NewReader(data), opts...)
+}
+
+// IngestJSONFile will parse the given JSON file, as produced by MarshalJSON,
+// and return a slice of test suite definitions.
+func IngestJSONFile(filename string, opts ...Option) ([]Suite, error) {
+	file, err := os.Open(filename) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint
+
+	return IngestJSONReader(file, opts...)
+}
+
+// IngestJSONReader will parse the given JSON reader, as produced by
+// MarshalJSON, and return a slice of test suite definitions.
+func IngestJSONReader(reader io.Reader, opts ...Option) ([]Suite, error) {
+	var encoded []wireSuite
+	if err := json.NewDecoder(reader).Decode(&encoded); err != nil {
+		return nil, err
+	}
+
+	suites := make([]Suite, len(encoded))
+	for index, suite := range encoded {
+		suites[index] = suite.decode()
+	}
+
+	return newOptions(opts).finish(suites)
+}
+
+// MarshalJSON returns the indented JSON encoding of the given suites.
+//
+// Suites are encoded using the json tags of their fields. Test errors are
+// encoded as an object with message, type, and body fields, and durations are
+// encoded as strings such as "1m2.5s", as accepted by time.ParseDuration.
+// Documents produced by MarshalJSON can be ingested again by IngestJSON
+// without loss.
+func MarshalJSON(suites []Suite) ([]byte, error) {
+	encoded := make([]wireSuite, len(suites))
+	for index, suite := range suites {
+		encoded[index] = encodeWireSuite(suite)
+	}
+
+	return encodeJSON(encoded, "  ")
+}
+
+// encodeJSON returns the JSON encoding of the given value, using the given
+// indent. Unlike json.Marshal, characters such as "<" and ">" are not escaped,
+// since they commonly appear in suite names and test output.
+func encodeJSON(value interface{}, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
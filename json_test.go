@@ -0,0 +1,149 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMarshalJSONRoundTrip(t *testing.T) {
+	reports, err := IngestDirAuto("testdata")
+	assertNoError(t, err)
+
+	for _, report := range reports {
+		report := report
+
+		t.Run(filepath.Base(report.Filename), func(t *testing.T) {
+			data, err := MarshalJSON(report.Suites)
+			assertNoError(t, err)
+
+			actual, err := IngestJSON(data)
+			assertNoError(t, err)
+
+			// Timestamps are written with their offset, so only their location may
+			// differ.
+			assertEqual(t, utcTimestamps(report.Suites), utcTimestamps(actual))
+		})
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	suites := []Suite{
+		{
+			Name:      "suite",
+			Timestamp: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+			Tests: []Test{
+				{
+					Name:     "failed",
+					Duration: 1500 * time.Millisecond,
+					Status:   StatusFailed,
+					Error: Error{
+						Message: "expected <true>",
+						Type:    "AssertionError",
+						Body:    "at example.go:12",
+					},
+					Attempts: []Attempt{
+						{Status: StatusFailed, Duration: 90 * time.Second},
+					},
+				},
+			},
+		},
+	}
+
+	expected := `[
+  {
+    "name": "suite",
+    "package": "",
+    "timestamp": "2021-01-02T03:04:05Z",
+    "declared": {
+      "tests": 0,
+      "passed": 0,
+      "skipped": 0,
+      "failed": 0,
+      "error": 0,
+      "duration": "0s"
+    },
+    "tests": [
+      {
+        "name": "failed",
+        "classname": "",
+        "duration": "1.5s",
+        "status": "failed",
+        "message": "",
+        "error": {
+          "message": "expected <true>",
+          "type": "AssertionError",
+          "body": "at example.go:12"
+        },
+        "attempts": [
+          {
+            "status": "failed",
+            "duration": "1m30s"
+          }
+        ],
+        "properties": null
+      }
+    ],
+    "totals": {
+      "tests": 0,
+      "passed": 0,
+      "skipped": 0,
+      "failed": 0,
+      "error": 0,
+      "duration": "0s"
+    }
+  }
+]`
+
+	actual, err := MarshalJSON(suites)
+	assertNoError(t, err)
+	assertEqual(t, expected, string(actual))
+}
+
+func TestMarshalJSONDefault(t *testing.T) {
+	// The wire format of MarshalJSON does not change the default encoding of
+	// the exported types.
+	data, err := json.Marshal(Totals{Tests: 1, Duration: time.Second})
+	assertNoError(t, err)
+	assertEqual(t, `{"tests":1,"passed":0,"skipped":0,"failed":0,"error":0,"duration":1000000000}`, string(data))
+}
+
+func TestIngestJSONDurations(t *testing.T) {
+	suites, err := IngestJSON([]byte(`[{"tests": [
+		{"name": "text", "duration": "2m3.5s"},
+		{"name": "nanoseconds", "duration": 1500}
+	]}]`))
+	assertNoError(t, err)
+	assertEqual(t, 123500*time.Millisecond, suites[0].Tests[0].Duration)
+	assertEqual(t, 1500*time.Nanosecond, suites[0].Tests[1].Duration)
+	assertEqual(t, nil, suites[0].Tests[0].Error)
+
+	_, err = IngestJSON([]byte(`[{"totals": {"duration": "soon"}}]`))
+	assertError(t, err, `invalid duration "soon"`)
+}
+
+// utcTimestamps converts the timestamps of the given suites, and of all of
+// their nested suites, to UTC.
+func utcTimestamps(suites []Suite) []Suite {
+	converted := make([]Suite, len(suites))
+
+	for index, suite := range suites {
+		if !suite.Timestamp.IsZero() {
+			suite.Timestamp = suite.Timestamp.UTC()
+		}
+
+		if suite.Suites != nil {
+			suite.Suites = utcTimestamps(suite.Suites)
+		}
+
+		converted[index] = suite
+	}
+
+	return converted
+}
@@ -0,0 +1,282 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import "strings"
+
+// MergePolicy decides which test is retained when merging multiple tests
+// with the same name and classname, within the same suite.
+type MergePolicy string
+
+const (
+	// MergeLastWins retains the test that was given last. This suits retries
+	// that are uploaded in the order they were run.
+	MergeLastWins MergePolicy = "last"
+
+	// MergeWorstWins retains the test with the worst status, where an error
+	// is worse than a failure, which is worse than a pass, which is worse
+	// than a skip. Ties are won by the test that was given last.
+	MergeWorstWins MergePolicy = "worst"
+
+	// MergeBestWins retains a passed test if there is one, and otherwise
+	// falls back to the worst status. When a passed test is retained, every
+	// failed or erroneous test is recorded as one of its rerun attempts, so
+	// that the retained test is considered flaky.
+	MergeBestWins MergePolicy = "best"
+)
+
+// Merge combines the given suites into a single tree of suites. Suites with
+// the same name and package are merged together, as are their nested suites,
+// and tests with the same name and classname within a merged suite are
+// deduplicated according to the given policy. Suites and tests retain the
+// order in which they were first given, and totals are recalculated.
+//
+// Suites that are merged together have their properties, attributes, IDs,
+// and hostnames combined, with later values taking precedence, and their
+// output concatenated. The earliest timestamp is retained. Since declared
+// counts may no longer describe the deduplicated tests of a suite, they are
+// discarded from every suite.
+//
+// The given suites are not modified.
+func Merge(suites []Suite, policy MergePolicy) []Suite {
+	merged := mergeSuites(suites, policy)
+	aggregateAll(merged)
+
+	return merged
+}
+
+// mergeSuites merges all suites with the same name and package, and then
+// recursively merges their nested suites.
+func mergeSuites(suites []Suite, policy MergePolicy) []Suite {
+	type key struct {
+		name string
+		pkg  string
+	}
+
+	var (
+		merged  []Suite
+		indices = make(map[key]int)
+	)
+
+	for _, suite := range suites {
+		k := key{suite.Name, suite.Package}
+
+		index, found := indices[k]
+		if !found {
+			indices[k] = len(merged)
+			suite.Attributes = copyStrings(suite.Attributes)
+			suite.Tests = mergeTests(nil, suite.Tests, policy)
+			suite.Suites = append([]Suite(nil), suite.Suites...)
+			merged = append(merged, suite)
+
+			continue
+		}
+
+		merged[index] = mergeSuite(merged[index], suite, policy)
+	}
+
+	for index := range merged {
+		for _, name := range declaredAttrs {
+			delete(merged[index].Attributes, name)
+		}
+
+		merged[index].Declared = Totals{}
+		merged[index].Suites = mergeSuites(merged[index].Suites, policy)
+	}
+
+	return merged
+}
+
+// mergeSuite combines the given suites, which share the same name and
+// package. Nested suites are only concatenated, and are merged later.
+func mergeSuite(first, second Suite, policy MergePolicy) Suite {
+	timestamp, found := first.Attributes["timestamp"]
+
+	first.Attributes = mergeMaps(first.Attributes, second.Attributes)
+	first.Properties = mergeMaps(first.Properties, second.Properties)
+
+	if second.ID != "" {
+		first.ID = second.ID
+	}
+
+	if second.Hostname != "" {
+		first.Hostname = second.Hostname
+	}
+
+	// The timestamp attribute follows whichever timestamp is retained.
+	if first.Timestamp.IsZero() || (!second.Timestamp.IsZero() && second.Timestamp.Before(first.Timestamp)) {
+		first.Timestamp = second.Timestamp
+	} else if found {
+		first.Attributes["timestamp"] = timestamp
+	} else {
+		delete(first.Attributes, "timestamp")
+	}
+
+	first.SystemOut = joinOutput(first.SystemOut, second.SystemOut)
+	first.SystemErr = joinOutput(first.SystemErr, second.SystemErr)
+	first.Tests = mergeTests(first.Tests, second.Tests, policy)
+	first.Suites = append(first.Suites, second.Suites...)
+
+	return first
+}
+
+// mergeTests appends the given tests to the given merged tests, replacing any
+// test with the same name and classname according to the given policy.
+func mergeTests(merged []Test, tests []Test, policy MergePolicy) []Test {
+	type key struct {
+		name      string
+		classname string
+	}
+
+	// Copy the existing tests, so that the original slice is not modified.
+	merged = append([]Test(nil), merged...)
+
+	indices := make(map[key]int, len(merged)+len(tests))
+	for index, test := range merged {
+		indices[key{test.Name, test.Classname}] = index
+	}
+
+	for _, test := range tests {
+		k := key{test.Name, test.Classname}
+
+		index, found := indices[k]
+		if !found {
+			indices[k] = len(merged)
+			merged = append(merged, test)
+
+			continue
+		}
+
+		merged[index] = mergeTest(merged[index], test, policy)
+	}
+
+	return merged
+}
+
+// mergeTest returns whichever of the given tests should be retained according
+// to the given policy.
+func mergeTest(first, second Test, policy MergePolicy) Test {
+	switch policy {
+	case MergeWorstWins:
+		if severity(first.Status) > severity(second.Status) {
+			return first
+		}
+
+		return second
+
+	case MergeBestWins:
+		switch {
+		case first.Status == StatusPassed && second.Status == StatusPassed:
+			second.Attempts = append(append([]Attempt(nil), first.Attempts...), second.Attempts...)
+
+			return second
+		case first.Status == StatusPassed:
+			first.Attempts = append(append([]Attempt(nil), first.Attempts...), rerun(second)...)
+
+			return first
+		case second.Status == StatusPassed:
+			second.Attempts = append(rerun(first), second.Attempts...)
+
+			return second
+		}
+
+		return mergeTest(first, second, MergeWorstWins)
+
+	default:
+		return second
+	}
+}
+
+// rerun returns the attempts of the given test, and the test itself as a
+// final attempt if it failed or errored. All attempts are marked as reruns.
+func rerun(test Test) []Attempt {
+	attempts := make([]Attempt, 0, len(test.Attempts)+1)
+	for _, attempt := range test.Attempts {
+		attempt.Rerun = true
+		attempts = append(attempts, attempt)
+	}
+
+	if test.Status != StatusFailed && test.Status != StatusError {
+		return attempts
+	}
+
+	// Only tests without attempts of their own need to be recorded.
+	if len(test.Attempts) > 0 {
+		return attempts
+	}
+
+	details := errorDetails(test.Error)
+
+	return append(attempts, Attempt{
+		Status:    test.Status,
+		Rerun:     true,
+		Message:   test.Message,
+		Type:      details.Type,
+		Body:      details.Body,
+		SystemOut: test.SystemOut,
+		SystemErr: test.SystemErr,
+		Duration:  test.Duration,
+	})
+}
+
+// severity ranks the given status, from least to most severe.
+func severity(status Status) int {
+	switch status {
+	case StatusSkipped:
+		return 1
+	case StatusPassed:
+		return 2
+	case StatusFailed:
+		return 3
+	case StatusError:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// aggregateAll calculates the totals of all given suites, and of all of
+// their nested suites.
+func aggregateAll(suites []Suite) {
+	for index := range suites {
+		aggregateAll(suites[index].Suites)
+		suites[index].Aggregate()
+	}
+}
+
+// mergeMaps returns a new map with the entries of both given maps, where
+// entries in the second map take precedence.
+func mergeMaps(first, second map[string]string) map[string]string {
+	if first == nil && second == nil {
+		return nil
+	}
+
+	merged := make(map[string]string, len(first)+len(second))
+
+	for name, value := range first {
+		merged[name] = value
+	}
+
+	for name, value := range second {
+		merged[name] = value
+	}
+
+	return merged
+}
+
+// joinOutput concatenates the given outputs, ensuring that they are
+// separated by a newline.
+func joinOutput(first, second string) string {
+	if first == "" || second == "" {
+		return first + second
+	}
+
+	if !strings.HasSuffix(first, "\n") {
+		first += "\n"
+	}
+
+	return first + second
+}
@@ -0,0 +1,126 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	shard := func(status Status, output string) Suite {
+		return Suite{
+			Name:       "pkg/foo",
+			Hostname:   output,
+			Attributes: map[string]string{"name": "pkg/foo", "tests": "2", "hostname": output},
+			Declared:   Totals{Tests: 2},
+			SystemOut:  output,
+			Tests: []Test{
+				{Name: "TestRetried", Status: status, Duration: time.Second, Error: errorFor(status)},
+			},
+			Suites: []Suite{
+				{
+					Name:  "nested",
+					Tests: []Test{{Name: "TestNested", Status: StatusPassed}},
+				},
+			},
+		}
+	}
+
+	suites := []Suite{
+		shard(StatusFailed, "first"),
+		{Name: "pkg/bar", Tests: []Test{{Name: "TestBar", Status: StatusSkipped}}},
+		shard(StatusPassed, "second"),
+		shard(StatusError, "third"),
+	}
+
+	tests := []struct {
+		policy MergePolicy
+		status Status
+		flaky  bool
+	}{
+		{policy: MergeLastWins, status: StatusError},
+		{policy: MergeWorstWins, status: StatusError},
+		{policy: MergeBestWins, status: StatusPassed, flaky: true},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("#%d - %s", index+1, test.policy)
+
+		t.Run(name, func(t *testing.T) {
+			merged := Merge(suites, test.policy)
+
+			assertLen(t, merged, 2)
+			assertEqual(t, "pkg/foo", merged[0].Name)
+			assertEqual(t, "pkg/bar", merged[1].Name)
+
+			foo := merged[0]
+			assertLen(t, foo.Tests, 1)
+			assertLen(t, foo.Suites, 1)
+			assertLen(t, foo.Suites[0].Tests, 1)
+			assertEqual(t, "first\nsecond\nthird", foo.SystemOut)
+			assertEqual(t, map[string]string{"name": "pkg/foo", "hostname": "third"}, foo.Attributes)
+			assertEqual(t, "third", foo.Hostname)
+			assertEqual(t, Totals{}, foo.Declared)
+			assertEqual(t, test.status, foo.Tests[0].Status)
+			assertEqual(t, test.flaky, foo.Tests[0].Flaky())
+			assertEqual(t, 2, foo.Totals.Tests)
+			assertEqual(t, 1, foo.Suites[0].Totals.Tests)
+		})
+	}
+
+	// The original suites are not modified.
+	assertLen(t, suites[0].Tests, 1)
+	assertEqual(t, StatusFailed, suites[0].Tests[0].Status)
+	assertLen(t, suites[2].Tests[0].Attempts, 0)
+	assertEqual(t, "2", suites[0].Attributes["tests"])
+}
+
+func TestMergeBestWinsAttempts(t *testing.T) {
+	merged := Merge([]Suite{
+		{Tests: []Test{{Name: "test", Status: StatusFailed, Message: "boom", Error: Error{Message: "boom", Body: "trace"}}}},
+		{Tests: []Test{{Name: "test", Status: StatusPassed}}},
+	}, MergeBestWins)
+
+	assertEqual(t, []Attempt{
+		{Status: StatusFailed, Rerun: true, Message: "boom", Body: "trace"},
+	}, merged[0].Tests[0].Attempts)
+}
+
+func errorFor(status Status) error {
+	if status == StatusFailed || status == StatusError {
+		return Error{Message: string(status)}
+	}
+
+	return nil
+}
+
+func TestMergeSingleSuite(t *testing.T) {
+	suites := []Suite{
+		{
+			Name:       "suite",
+			Attributes: map[string]string{"name": "suite", "tests": "2", "failures": "1", "time": "2.000"},
+			Declared:   Totals{Tests: 2, Failed: 1, Duration: 2 * time.Second},
+			Tests: []Test{
+				{Name: "TestRetried", Status: StatusFailed, Duration: time.Second, Error: errorFor(StatusFailed)},
+				{Name: "TestRetried", Status: StatusPassed, Duration: time.Second},
+			},
+		},
+	}
+
+	merged := Merge(suites, MergeLastWins)
+
+	assertLen(t, merged, 1)
+	assertLen(t, merged[0].Tests, 1)
+	assertEqual(t, map[string]string{"name": "suite"}, merged[0].Attributes)
+	assertEqual(t, Totals{}, merged[0].Declared)
+	assertEqual(t, Totals{Tests: 1, Passed: 1, Duration: time.Second}, merged[0].Totals)
+	assertNoError(t, merged[0].Validate())
+
+	// The original suite is not modified.
+	assertEqual(t, "2", suites[0].Attributes["tests"])
+}
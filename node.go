@@ -5,7 +5,11 @@
 
 package junit
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
 
 type xmlNode struct {
 	XMLName xml.Name
@@ -48,3 +52,37 @@ func attrMap(attrs []xml.Attr) map[string]string {
 
 	return attributes
 }
+
+// attrTotals returns the counts and time declared by the attributes of the
+// given tag, using the given attribute names, for formats such as NUnit and
+// xUnit.net. Each count is the sum of every attribute given for it. The time
+// is read from a "duration" attribute, as written by NUnit, or from a "time"
+// attribute, as written by xUnit.net, which takes precedence if both exist.
+func attrTotals(root xmlNode, tests string, passed, failed, errors, skipped []string) Totals {
+	count := func(names ...string) int {
+		var total int
+
+		for _, name := range names {
+			value, _ := strconv.Atoi(strings.TrimSpace(root.Attr(name)))
+			total += value
+		}
+
+		return total
+	}
+
+	totals := Totals{
+		Tests:   count(tests),
+		Passed:  count(passed...),
+		Failed:  count(failed...),
+		Error:   count(errors...),
+		Skipped: count(skipped...),
+	}
+
+	for _, name := range []string{"duration", "time"} {
+		if value := root.Attr(name); value != "" {
+			totals.Duration = duration(value)
+		}
+	}
+
+	return totals
+}
@@ -0,0 +1,177 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// IngestNUnit will parse the given NUnit 3 XML data and return a slice of all
+// contained test suite definitions.
+//
+// Each "test-suite" tag becomes a suite, with nested test suites, such as
+// namespaces, fixtures, and parameterized methods, becoming nested suites.
+// Each "test-case" tag becomes a test. The "result" attribute becomes the test
+// status, where failures labelled as errors, invalid, or cancelled are
+// considered errors, inconclusive results are considered skipped, and warnings
+// are considered passed. Failure and skip reasons become the test message, and
+// failure stack traces become the body of the test error. Test properties,
+// such as categories, become the test properties.
+func IngestNUnit(data []byte, opts ...Option) ([]Suite, error) {
+	return IngestNUnitReader(bytes.NewReader(data), opts...)
+}
+
+// IngestNUnitFile will parse the given NUnit 3 XML file and return a slice of
+// all contained test suite definitions.
+func IngestNUnitFile(filename string, opts ...Option) ([]Suite, error) {
+	file, err := os.Open(filename) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint
+
+	return IngestNUnitReader(file, opts...)
+}
+
+// IngestNUnitReader will parse the given NUnit 3 XML reader and return a slice
+// of all contained test suite definitions.
+func IngestNUnitReader(reader io.Reader, opts ...Option) ([]Suite, error) {
+	config := newOptions(opts)
+
+	nodes, err := parse(reader, config)
+	if err != nil {
+		return nil, err
+	}
+
+	suites := make([]Suite, 0)
+
+	var find func(nodes []xmlNode)
+
+	find = func(nodes []xmlNode) {
+		for _, node := range nodes {
+			switch node.XMLName.Local {
+			case "test-suite":
+				suites = append(suites, ingestNUnitSuite(node))
+			case "test-run":
+				find(node.Nodes)
+			}
+		}
+	}
+
+	find(nodes)
+
+	return config.finish(suites)
+}
+
+func ingestNUnitSuite(root xmlNode) Suite {
+	suite := Suite{
+		Name:      root.Attr("name"),
+		ID:        root.Attr("id"),
+		Timestamp: timestamp(root.Attr("start-time")),
+		Declared:  attrTotals(root, "total", []string{"passed", "warnings"}, []string{"failed"}, nil, []string{"skipped", "inconclusive"}),
+	}
+
+	for _, node := range root.Nodes {
+		switch node.XMLName.Local {
+		case "test-suite":
+			suite.Suites = append(suite.Suites, ingestNUnitSuite(node))
+		case "test-case":
+			suite.Tests = append(suite.Tests, ingestNUnitTest(node))
+		case "properties":
+			suite.Properties = joinedProperties(node, "property")
+		case "output":
+			suite.SystemOut = string(node.Content)
+		}
+	}
+
+	suite.Aggregate()
+
+	return suite
+}
+
+func ingestNUnitTest(root xmlNode) Test {
+	test := Test{
+		Name:      root.Attr("name"),
+		Classname: root.Attr("classname"),
+		Duration:  duration(root.Attr("duration")),
+	}
+
+	var details Error
+
+	for _, node := range root.Nodes {
+		switch node.XMLName.Local {
+		case "failure", "reason":
+			for _, child := range node.Nodes {
+				switch child.XMLName.Local {
+				case "message":
+					details.Message = string(child.Content)
+				case "stack-trace":
+					details.Body = string(child.Content)
+				}
+			}
+		case "properties":
+			test.Properties = joinedProperties(node, "property")
+		case "output":
+			test.SystemOut = string(node.Content)
+		}
+	}
+
+	switch root.Attr("result") {
+	case "Failed":
+		test.Status = StatusFailed
+
+		switch root.Attr("label") {
+		case "Error", "Invalid", "Cancelled":
+			test.Status = StatusError
+			details.Type = root.Attr("label")
+		}
+	case "Skipped", "Inconclusive":
+		test.Status = StatusSkipped
+	default:
+		test.Status = StatusPassed
+	}
+
+	test.Message = details.Message
+
+	if test.Status == StatusFailed || test.Status == StatusError {
+		test.Error = details
+		test.Attempts = []Attempt{{
+			Status:   test.Status,
+			Message:  details.Message,
+			Type:     details.Type,
+			Body:     details.Body,
+			Duration: test.Duration,
+		}}
+	}
+
+	return test
+}
+
+// joinedProperties returns the name and value attributes of the tags with the
+// given name, within the given tag. Properties that are given more than once,
+// such as categories, have their values joined by commas.
+func joinedProperties(root xmlNode, tag string) map[string]string {
+	props := make(map[string]string, len(root.Nodes))
+
+	for _, node := range root.Nodes {
+		if node.XMLName.Local != tag {
+			continue
+		}
+
+		name := node.Attr("name")
+		if existing, found := props[name]; found {
+			props[name] = existing + "," + node.Attr("value")
+
+			continue
+		}
+
+		props[name] = node.Attr("value")
+	}
+
+	return props
+}
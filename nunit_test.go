@@ -0,0 +1,79 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIngestNUnitFile(t *testing.T) {
+	suites, err := IngestNUnitFile("testdata/nunit3.xml")
+	assertNoError(t, err)
+	assertLen(t, suites, 1)
+
+	assembly := suites[0]
+	assertEqual(t, "Calculator.Tests.dll", assembly.Name)
+	assertEqual(t, "0-1007", assembly.ID)
+	assertEqual(t, time.Date(2021, 3, 4, 10, 11, 12, 0, time.UTC), assembly.Timestamp)
+	assertEqual(t, "4242", assembly.Properties["_PID"])
+	assertEqual(t, Totals{Tests: 5, Passed: 2, Skipped: 1, Failed: 2, Duration: 790 * time.Millisecond}, assembly.Declared)
+	assertEqual(t, Totals{Tests: 5, Passed: 2, Skipped: 1, Failed: 1, Error: 1, Duration: 732 * time.Millisecond}, assembly.Totals)
+	assertLen(t, assembly.Suites, 1)
+	assertLen(t, assembly.Suites[0].Suites, 1)
+
+	fixture := assembly.Suites[0].Suites[0]
+	assertEqual(t, "CalculatorTests", fixture.Name)
+	assertEqual(t, "fixture setup\n", fixture.SystemOut)
+	assertLen(t, fixture.Tests, 3)
+	assertLen(t, fixture.Suites, 1)
+
+	assertEqual(t, "Add", fixture.Tests[0].Name)
+	assertEqual(t, "Calculator.CalculatorTests", fixture.Tests[0].Classname)
+	assertEqual(t, StatusPassed, fixture.Tests[0].Status)
+	assertEqual(t, 12*time.Millisecond, fixture.Tests[0].Duration)
+	assertEqual(t, map[string]string{"Category": "Fast,Math"}, fixture.Tests[0].Properties)
+	assertEqual(t, "adding numbers\n", fixture.Tests[0].SystemOut)
+
+	assertEqual(t, StatusError, fixture.Tests[1].Status)
+	assertEqual(t, "System.DivideByZeroException : Attempted to divide by zero.", fixture.Tests[1].Message)
+	assertEqual(t, Error{
+		Message: "System.DivideByZeroException : Attempted to divide by zero.",
+		Type:    "Error",
+		Body:    "   at Calculator.Calculator.Divide(Int32 a, Int32 b) in /src/Calculator/Calculator.cs:line 12\n   at Calculator.CalculatorTests.Divide() in /src/Calculator.Tests/CalculatorTests.cs:line 21",
+	}, fixture.Tests[1].Error)
+
+	assertEqual(t, StatusSkipped, fixture.Tests[2].Status)
+	assertEqual(t, "Not implemented yet", fixture.Tests[2].Message)
+	assertEqual(t, nil, fixture.Tests[2].Error)
+
+	parameterized := fixture.Suites[0]
+	assertEqual(t, "Multiply", parameterized.Name)
+	assertLen(t, parameterized.Tests, 2)
+	assertEqual(t, StatusFailed, parameterized.Tests[1].Status)
+	assertEqual(t, "  Expected: 5\n  But was:  4\n", parameterized.Tests[1].Message)
+}
+
+func TestIngestNUnitEmpty(t *testing.T) {
+	suites, err := IngestNUnit([]byte(`<test-run id="0" total="0" />`))
+	assertNoError(t, err)
+	assertLen(t, suites, 0)
+}
+
+func TestIngestNUnitWarnings(t *testing.T) {
+	data := []byte(`<test-run id="0" total="2" passed="1" failed="0" warnings="1" inconclusive="0" skipped="0">
+  <test-suite type="TestFixture" id="0-1000" name="Fixture" total="2" passed="1" failed="0" warnings="1" inconclusive="0" skipped="0">
+    <test-case id="0-1001" name="Passed" result="Passed" duration="0.001" />
+    <test-case id="0-1002" name="Warned" result="Warning" duration="0.001" />
+  </test-suite>
+</test-run>`)
+
+	suites, err := IngestNUnit(data, WithStrict())
+	assertNoError(t, err)
+	assertLen(t, suites, 1)
+	assertEqual(t, Totals{Tests: 2, Passed: 2, Duration: 2 * time.Millisecond}, suites[0].Totals)
+	assertEqual(t, StatusPassed, suites[0].Tests[1].Status)
+}
@@ -0,0 +1,131 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"io"
+	"strings"
+)
+
+// Option configures the behavior of ingestion. Options can be given to any
+// of the ingestion functions, and are applied in order.
+type Option func(*options)
+
+// options holds the configuration for a single ingestion.
+type options struct {
+	// filter reports if a file found while searching a directory should be
+	// ingested.
+	filter func(path string) bool
+
+	// charsetReader converts documents that declare a non-UTF-8 encoding.
+	charsetReader func(charset string, input io.Reader) (io.Reader, error)
+
+	// strict causes suites with inconsistent declared counts to fail
+	// ingestion.
+	strict bool
+
+	// trx causes files ending with ".trx" to be ingested as Visual Studio
+	// TRX files.
+	trx bool
+}
+
+// newOptions returns the default configuration, with the given options
+// applied.
+func newOptions(opts []Option) options {
+	var config options
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if config.filter == nil {
+		trx := config.trx
+		config.filter = func(path string) bool {
+			return strings.HasSuffix(path, ".xml") || (trx && isTRX(path))
+		}
+	}
+
+	return config
+}
+
+// finish applies the configuration to the given suites, which were ingested
+// from a format other than JUnit XML, once they have been fully read. If
+// strict ingestion is enabled, every suite and nested suite is validated.
+func (config options) finish(suites []Suite) ([]Suite, error) {
+	if !config.strict {
+		return suites, nil
+	}
+
+	var validate func(suites []Suite) error
+
+	validate = func(suites []Suite) error {
+		for _, suite := range suites {
+			if err := validate(suite.Suites); err != nil {
+				return err
+			}
+
+			if err := suite.Validate(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := validate(suites); err != nil {
+		return nil, err
+	}
+
+	return suites, nil
+}
+
+// WithFileFilter configures which files are ingested when searching a
+// directory. The given function is called with the path of every regular
+// file that is found, and reports if that file should be ingested. By
+// default, only files ending with ".xml" are ingested, along with files
+// ending with ".trx" if WithTRX is given.
+func WithFileFilter(filter func(path string) bool) Option {
+	return func(config *options) {
+		config.filter = filter
+	}
+}
+
+// WithCharsetReader configures how documents that declare a non-UTF-8
+// encoding are handled. The given function is called with the declared
+// charset, and returns a reader that converts the input to UTF-8. By default,
+// such documents cannot be ingested.
+func WithCharsetReader(charsetReader func(charset string, input io.Reader) (io.Reader, error)) Option {
+	return func(config *options) {
+		config.charsetReader = charsetReader
+	}
+}
+
+// WithStrict enables strict ingestion. Every suite has its declared "tests",
+// "failures", "errors", "skipped", and "time" attributes compared against the
+// tests that it actually contains, and ingestion fails with a
+// *ValidationError at the first suite where they disagree. Suites ingested
+// from other formats have their declared counts compared instead, as
+// described by Suite.Validate, and TRX files have the counters of each test
+// run compared against the results that it contains.
+func WithStrict() Option {
+	return func(config *options) {
+		config.strict = true
+	}
+}
+
+// WithTRX enables the ingestion of Visual Studio TRX files alongside JUnit XML
+// files. Any file ending with ".trx" is ingested as a TRX file, and such files
+// are also ingested by default when searching a directory.
+func WithTRX() Option {
+	return func(config *options) {
+		config.trx = true
+	}
+}
+
+// isTRX reports if the given path names a Visual Studio TRX file.
+func isTRX(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".trx")
+}
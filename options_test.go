@@ -0,0 +1,72 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithFileFilter(t *testing.T) {
+	suites, err := IngestDir("testdata", WithFileFilter(func(path string) bool {
+		return filepath.Base(path) == "phpunit.xml"
+	}))
+
+	assertNoError(t, err)
+	assertLen(t, suites, 1)
+	assertEqual(t, "/untitled/tests", suites[0].Name)
+}
+
+func TestWithCharsetReader(t *testing.T) {
+	input := []byte("<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n<testsuite name=\"caf\xe9\" />")
+
+	_, err := Ingest(input)
+	assertError(t, err, `xml: encoding "ISO-8859-1" declared but Decoder.CharsetReader is nil`)
+
+	// A minimal Latin-1 decoder, where every byte is its own code point.
+	latin1 := func(charset string, input io.Reader) (io.Reader, error) {
+		var buf bytes.Buffer
+
+		data, err := ioutil.ReadAll(input)
+		for _, b := range data {
+			buf.WriteRune(rune(b))
+		}
+
+		return &buf, err
+	}
+
+	suites, err := Ingest(input, WithCharsetReader(latin1))
+	assertNoError(t, err)
+	assertLen(t, suites, 1)
+	assertEqual(t, "café", suites[0].Name)
+}
+
+func TestWithCharsetReaderFormats(t *testing.T) {
+	input := []byte("<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n<test-run><test-suite name=\"caf\xe9\" /></test-run>")
+
+	_, err := IngestNUnit(input)
+	assertError(t, err, `xml: encoding "ISO-8859-1" declared but Decoder.CharsetReader is nil`)
+
+	// A minimal Latin-1 decoder, where every byte is its own code point.
+	latin1 := func(charset string, input io.Reader) (io.Reader, error) {
+		var buf bytes.Buffer
+
+		data, err := ioutil.ReadAll(input)
+		for _, b := range data {
+			buf.WriteRune(rune(b))
+		}
+
+		return &buf, err
+	}
+
+	suites, err := IngestNUnit(input, WithCharsetReader(latin1))
+	assertNoError(t, err)
+	assertLen(t, suites, 1)
+	assertEqual(t, "café", suites[0].Name)
+}
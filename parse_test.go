@@ -170,7 +170,7 @@ func TestParse(t *testing.T) {
 		name := fmt.Sprintf("#%d - %s", index+1, test.title)
 
 		t.Run(name, func(t *testing.T) {
-			actual, err := parse(bytes.NewReader(test.input))
+			actual, err := parse(bytes.NewReader(test.input), options{})
 			assertNoError(t, err)
 
 			assertEqual(t, test.expected, actual)
@@ -0,0 +1,324 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package render
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/joshdk/go-junit"
+)
+
+// HTMLOption configures the behavior of HTML.
+type HTMLOption func(*htmlOptions)
+
+type htmlOptions struct {
+	title   string
+	slowest int
+}
+
+// WithTitle configures the title of the rendered page. By default, the title
+// is "Test Report".
+func WithTitle(title string) HTMLOption {
+	return func(config *htmlOptions) {
+		config.title = title
+	}
+}
+
+// WithSlowest configures the number of tests and suites that are listed in
+// the slowest tests and slowest suites tables. By default, 10 of each are
+// listed, and a limit of zero omits the tables.
+func WithSlowest(limit int) HTMLOption {
+	return func(config *htmlOptions) {
+		config.slowest = limit
+	}
+}
+
+// HTML writes the given suites to the given writer as a single, self-contained
+// HTML page, which can be published as a build artifact.
+//
+// The page includes the totals of every suite as a bar, with nested suites
+// shown as collapsible sections. Tests can be filtered by their status, and
+// searched by their name. The error, stdout, and stderr of each test can be
+// expanded, and the slowest tests and suites are listed in separate tables.
+// The page does not reference any external assets.
+func HTML(w io.Writer, suites []junit.Suite, opts ...HTMLOption) error {
+	config := htmlOptions{
+		title:   "Test Report",
+		slowest: 10,
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	page := htmlPage{
+		Title:    config.title,
+		Totals:   htmlTotalsOf(junit.Sum(suites)),
+		Statuses: statuses,
+		Suites:   htmlSuites(nil, suites),
+	}
+
+	for _, slow := range slowest(suites, config.slowest) {
+		page.SlowestTests = append(page.SlowestTests, htmlTestOf(slow.name, slow.test))
+	}
+
+	page.SlowestSuites = slowestSuites(page.Suites, config.slowest)
+
+	return htmlTemplate.Execute(w, page)
+}
+
+type htmlPage struct {
+	Title         string
+	Totals        htmlTotals
+	Statuses      []junit.Status
+	Suites        []htmlSuite
+	SlowestTests  []htmlTest
+	SlowestSuites []htmlSuite
+}
+
+type htmlTotals struct {
+	junit.Totals
+	Duration string
+	Segments []htmlSegment
+}
+
+// htmlSegment is a part of a totals bar, representing the tests with a single
+// status.
+type htmlSegment struct {
+	Status junit.Status
+	Count  int
+}
+
+type htmlSuite struct {
+	Name      string
+	Path      string
+	Totals    htmlTotals
+	Duration  int64
+	Tests     []htmlTest
+	Suites    []htmlSuite
+	SystemOut string
+	SystemErr string
+}
+
+type htmlTest struct {
+	Name      string
+	Path      string
+	Search    string
+	Status    junit.Status
+	Flaky     bool
+	Duration  string
+	Message   string
+	Type      string
+	Body      string
+	SystemOut string
+	SystemErr string
+}
+
+// htmlSuites converts the given suites, which are nested within the given
+// chain of suites.
+func htmlSuites(parents []junit.Suite, suites []junit.Suite) []htmlSuite {
+	converted := make([]htmlSuite, 0, len(suites))
+
+	for _, suite := range suites {
+		suite.Aggregate()
+
+		chain := append(parents[:len(parents):len(parents)], suite)
+		names := make([]string, 0, len(chain))
+
+		for _, parent := range chain {
+			if name := suiteName(parent); name != "" {
+				names = append(names, name)
+			}
+		}
+
+		view := htmlSuite{
+			Name:      suiteName(suite),
+			Path:      strings.Join(names, " > "),
+			Totals:    htmlTotalsOf(suite.Totals),
+			Duration:  int64(suite.Totals.Duration),
+			Suites:    htmlSuites(chain, suite.Suites),
+			SystemOut: suite.SystemOut,
+			SystemErr: suite.SystemErr,
+		}
+
+		for _, test := range suite.Tests {
+			view.Tests = append(view.Tests, htmlTestOf(testName(chain, test), test))
+		}
+
+		converted = append(converted, view)
+	}
+
+	return converted
+}
+
+func htmlTestOf(name string, test junit.Test) htmlTest {
+	view := htmlTest{
+		Name:      test.Name,
+		Path:      name,
+		Search:    strings.ToLower(name),
+		Status:    test.Status,
+		Flaky:     test.Flaky(),
+		Duration:  formatDuration(test.Duration),
+		Message:   test.Message,
+		Type:      test.Details().Type,
+		Body:      test.Details().Body,
+		SystemOut: test.SystemOut,
+		SystemErr: test.SystemErr,
+	}
+
+	if view.Body == view.Message {
+		view.Body = ""
+	}
+
+	return view
+}
+
+func htmlTotalsOf(totals junit.Totals) htmlTotals {
+	view := htmlTotals{
+		Totals:   totals,
+		Duration: formatDuration(totals.Duration),
+	}
+
+	for _, status := range statuses {
+		if n := count(totals, status); n > 0 {
+			view.Segments = append(view.Segments, htmlSegment{status, n})
+		}
+	}
+
+	return view
+}
+
+// slowestSuites returns the given number of suites, including nested suites,
+// that took the longest, in order of decreasing duration.
+func slowestSuites(suites []htmlSuite, limit int) []htmlSuite {
+	var all []htmlSuite
+
+	var collect func(suites []htmlSuite)
+	collect = func(suites []htmlSuite) {
+		for _, suite := range suites {
+			if suite.Duration > 0 {
+				all = append(all, suite)
+			}
+
+			collect(suite.Suites)
+		}
+	}
+
+	collect(suites)
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Duration > all[j].Duration
+	})
+
+	if len(all) > limit {
+		all = all[:limit]
+	}
+
+	return all
+}
+
+// htmlTemplate renders a complete, self-contained report page.
+//
+//nolint:gochecknoglobals
+var htmlTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+:root { --passed: #2da44e; --failed: #cf222e; --error: #bc4c00; --skipped: #8c959f; }
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+h1, h2 { font-weight: 600; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { text-align: left; padding: 0.25em 1em 0.25em 0; }
+td.number { text-align: right; }
+pre { background: #f6f8fa; padding: 0.75em; overflow-x: auto; white-space: pre-wrap; }
+details { margin: 0.25em 0; }
+details.suite { border-left: 2px solid #d0d7de; padding-left: 1em; }
+summary { cursor: pointer; }
+.bar { display: inline-flex; width: 12em; height: 0.75em; vertical-align: middle; background: #eaeef2; }
+.bar span { display: block; }
+.passed { color: var(--passed); } .bar .passed { background: var(--passed); }
+.failed { color: var(--failed); } .bar .failed { background: var(--failed); }
+.error { color: var(--error); } .bar .error { background: var(--error); }
+.skipped { color: var(--skipped); } .bar .skipped { background: var(--skipped); }
+.status { font-weight: 600; text-transform: uppercase; font-size: 0.8em; }
+.duration, .counts { color: #656d76; }
+.test { margin-left: 1em; }
+.hidden { display: none; }
+#controls { margin: 1em 0; }
+#controls label { margin-right: 1em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{with .Totals}}<p>{{template "bar" .}} {{.Tests}} tests: {{.Passed}} passed, {{.Failed}} failed, {{.Error}} errors, {{.Skipped}} skipped <span class="duration">in {{.Duration}}</span></p>{{end}}
+<div id="controls">
+<input id="search" type="search" placeholder="Search tests">
+{{range .Statuses}}<label><input type="checkbox" class="filter" value="{{.}}" checked> <span class="{{.}}">{{.}}</span></label>{{end}}
+</div>
+<h2>Suites</h2>
+{{range .Suites}}{{template "suite" .}}{{end}}
+{{with .SlowestTests}}<h2>Slowest Tests</h2>
+<table>
+<tr><th>Test</th><th>Status</th><th>Duration</th></tr>
+{{range .}}<tr><td>{{.Path}}</td><td class="status {{.Status}}">{{.Status}}</td><td class="number">{{.Duration}}</td></tr>
+{{end}}</table>{{end}}
+{{with .SlowestSuites}}<h2>Slowest Suites</h2>
+<table>
+<tr><th>Suite</th><th>Tests</th><th>Duration</th></tr>
+{{range .}}<tr><td>{{.Path}}</td><td class="number">{{.Totals.Tests}}</td><td class="number">{{.Totals.Duration}}</td></tr>
+{{end}}</table>{{end}}
+<script>
+(function () {
+  var search = document.getElementById("search");
+  var filters = document.querySelectorAll(".filter");
+
+  function update() {
+    var query = search.value.toLowerCase();
+    var enabled = {};
+    filters.forEach(function (filter) { enabled[filter.value] = filter.checked; });
+
+    document.querySelectorAll(".test").forEach(function (test) {
+      var visible = enabled[test.dataset.status] && test.dataset.search.indexOf(query) >= 0;
+      test.classList.toggle("hidden", !visible);
+    });
+
+    var suites = Array.prototype.slice.call(document.querySelectorAll(".suite")).reverse();
+    suites.forEach(function (suite) {
+      suite.classList.toggle("hidden", !suite.querySelector(".test:not(.hidden)"));
+    });
+  }
+
+  search.addEventListener("input", update);
+  filters.forEach(function (filter) { filter.addEventListener("change", update); });
+})();
+</script>
+</body>
+</html>
+{{define "bar"}}<span class="bar">{{range .Segments}}<span class="{{.Status}}" style="flex-grow: {{.Count}}" title="{{.Count}} {{.Status}}"></span>{{end}}</span>{{end}}
+{{define "suite"}}<details class="suite"{{if or .Totals.Failed .Totals.Error}} open{{end}}>
+<summary>{{template "bar" .Totals}} <strong>{{.Name}}</strong> <span class="counts">{{.Totals.Passed}}/{{.Totals.Tests}} passed</span> <span class="duration">{{.Totals.Duration}}</span></summary>
+{{with .SystemOut}}<details><summary>stdout</summary><pre>{{.}}</pre></details>{{end}}
+{{with .SystemErr}}<details><summary>stderr</summary><pre>{{.}}</pre></details>{{end}}
+{{range .Suites}}{{template "suite" .}}{{end}}
+{{range .Tests}}<div class="test" data-status="{{.Status}}" data-search="{{.Search}}">
+{{if or .Message .Body .Type .SystemOut .SystemErr}}<details>
+<summary>{{template "test" .}}</summary>
+{{if .Type}}<p><code>{{.Type}}</code></p>{{end}}
+{{with .Body}}<pre>{{.}}</pre>{{end}}
+{{with .SystemOut}}<details><summary>stdout</summary><pre>{{.}}</pre></details>{{end}}
+{{with .SystemErr}}<details><summary>stderr</summary><pre>{{.}}</pre></details>{{end}}
+</details>{{else}}{{template "test" .}}{{end}}
+</div>
+{{end}}</details>
+{{end}}
+{{define "test"}}<span class="status {{.Status}}">{{.Status}}</span>{{if .Flaky}} <span class="status error">flaky</span>{{end}} {{.Name}} <span class="duration">{{.Duration}}</span>{{with .Message}} &mdash; {{.}}{{end}}{{end}}
+`))
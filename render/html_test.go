@@ -0,0 +1,60 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	assertNoError(t, HTML(&buf, example(), WithTitle("Nightly <build>")))
+
+	page := buf.String()
+
+	for _, expected := range []string{
+		"<title>Nightly &lt;build&gt;</title>",
+		"5 tests: 2 passed, 1 failed, 1 errors, 1 skipped",
+		`<span class="passed" style="flex-grow: 2" title="2 passed"></span>`,
+		`<details class="suite" open>`,
+		`data-search="outer &gt; inner &gt; pkg.inner &gt; fails &lt;badly&gt;"`,
+		"<p><code>AssertionError</code></p>",
+		"<pre>AssertionError: expected true\n\tat inner_test.go:12</pre>",
+		"<details><summary>stdout</summary><pre>some output\n</pre></details>",
+		`<span class="status error">flaky</span> flaky`,
+		"<h2>Slowest Tests</h2>",
+		"<h2>Slowest Suites</h2>",
+	} {
+		if !strings.Contains(page, expected) {
+			t.Fatalf("page did not contain %q:\n%s", expected, page)
+		}
+	}
+
+	// Tests are listed from slowest to fastest, omitting those without a
+	// duration.
+	slowest := page[strings.Index(page, "<h2>Slowest Tests</h2>"):strings.Index(page, "<h2>Slowest Suites</h2>")]
+	assertEqual(t, 4, strings.Count(slowest, "<tr><td>"))
+	assertEqual(t, true, strings.Index(slowest, "passes") < strings.Index(slowest, "fails"))
+
+	// No external assets are referenced.
+	for _, unexpected := range []string{"<link", "src=", "http://", "https://"} {
+		if strings.Contains(page, unexpected) {
+			t.Fatalf("page contained %q:\n%s", unexpected, page)
+		}
+	}
+}
+
+func TestHTMLWithSlowest(t *testing.T) {
+	var buf bytes.Buffer
+	assertNoError(t, HTML(&buf, example(), WithSlowest(0)))
+
+	page := buf.String()
+	if !strings.Contains(page, "<title>Test Report</title>") || strings.Contains(page, "Slowest") {
+		t.Fatalf("page did not omit the slowest tests and suites:\n%s", page)
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/joshdk/go-junit"
+)
+
+// MarkdownOption configures the behavior of Markdown.
+type MarkdownOption func(*markdownOptions)
+
+type markdownOptions struct {
+	heading   string
+	budget    int
+	bodyLines int
+}
+
+// WithHeading configures the heading of the rendered summary. By default, the
+// heading is "Test Results", and an empty heading is omitted.
+func WithHeading(heading string) MarkdownOption {
+	return func(config *markdownOptions) {
+		config.heading = heading
+	}
+}
+
+// WithBudget configures the maximum size, in bytes, of the rendered summary.
+// By default, the budget is 65536 bytes, which is the size limit of a GitHub
+// pull request comment. A budget of zero is unlimited.
+func WithBudget(budget int) MarkdownOption {
+	return func(config *markdownOptions) {
+		config.budget = budget
+	}
+}
+
+// WithBodyLines configures the maximum number of lines of each error body and
+// output that are included in the rendered summary. By default, 50 lines are
+// included.
+func WithBodyLines(lines int) MarkdownOption {
+	return func(config *markdownOptions) {
+		config.bodyLines = lines
+	}
+}
+
+// markdownDetail is the amount of detail included about each failed test.
+type markdownDetail int
+
+const (
+	// detailOutput includes the message, error body, and output of each test.
+	detailOutput markdownDetail = iota
+
+	// detailBody includes the message and error body of each test.
+	detailBody
+
+	// detailMessage includes the message of each test.
+	detailMessage
+
+	// detailName includes only the name of each test.
+	detailName
+)
+
+// Markdown writes the given suites to the given writer as a Markdown summary,
+// suitable for pull request comments and CI job summaries.
+//
+// The summary includes a table of the totals of all suites, followed by a
+// collapsible list of every failed or erroneous test, with its message,
+// truncated error body, and truncated output. If the summary would exceed the
+// configured budget, detail is removed until it fits. First the output of
+// each test is dropped, then the error bodies, then the messages, and finally
+// tests are omitted from the end of the list. The totals are always included,
+// even if they alone exceed the budget.
+func Markdown(w io.Writer, suites []junit.Suite, opts ...MarkdownOption) error {
+	config := markdownOptions{
+		heading:   "Test Results",
+		budget:    65536,
+		bodyLines: 50,
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	var failures []timed
+
+	junit.Walk(suites, func(parents []junit.Suite, test junit.Test) {
+		if failing(test) {
+			failures = append(failures, timed{testName(parents, test), test})
+		}
+	})
+
+	var summary []byte
+
+	for detail := detailOutput; detail <= detailName; detail++ {
+		summary = config.render(suites, failures, detail, len(failures))
+		if config.budget <= 0 || len(summary) <= config.budget {
+			break
+		}
+	}
+
+	// Omit as few tests from the end of the list as needed for the summary to
+	// fit, if possible.
+	if config.budget > 0 && len(summary) > config.budget {
+		limit := sort.Search(len(failures), func(limit int) bool {
+			return len(config.render(suites, failures, detailName, limit+1)) > config.budget
+		})
+
+		summary = config.render(suites, failures, detailName, limit)
+	}
+
+	_, err := w.Write(summary)
+
+	return err
+}
+
+// render renders a summary including the given amount of detail about the
+// given number of failed tests.
+func (config markdownOptions) render(suites []junit.Suite, failures []timed, detail markdownDetail, limit int) []byte {
+	var buf bytes.Buffer
+
+	if config.heading != "" {
+		fmt.Fprintf(&buf, "## %s\n\n", markdownEscape(config.heading))
+	}
+
+	totals := junit.Sum(suites)
+
+	buf.WriteString("| Tests | Passed | Failed | Errors | Skipped | Duration |\n")
+	buf.WriteString("| ---: | ---: | ---: | ---: | ---: | ---: |\n")
+	fmt.Fprintf(&buf, "| %d | %d | %d | %d | %d | %s |\n",
+		totals.Tests, totals.Passed, totals.Failed, totals.Error, totals.Skipped, formatDuration(totals.Duration))
+
+	if len(failures) == 0 {
+		return buf.Bytes()
+	}
+
+	noun := "tests"
+	if len(failures) == 1 {
+		noun = "test"
+	}
+
+	fmt.Fprintf(&buf, "\n<details>\n<summary>%d failed %s</summary>\n\n", len(failures), noun)
+
+	for index, failure := range failures {
+		if index == limit {
+			fmt.Fprintf(&buf, "- …and %d more\n", len(failures)-limit)
+
+			break
+		}
+
+		if index > 0 && detail < detailName {
+			buf.WriteByte('\n')
+		}
+
+		test := failure.test
+		fmt.Fprintf(&buf, "- **%s** %s (%s)\n", test.Status, markdownCode(failure.name), formatDuration(test.Duration))
+
+		if detail > detailMessage {
+			continue
+		}
+
+		message := test.Message
+		if message == "" {
+			message = test.Details().Message
+		}
+
+		if message = strings.TrimSpace(message); message != "" {
+			fmt.Fprintf(&buf, "\n  %s\n", strings.Replace(markdownEscape(message), "\n", "\n  ", -1))
+		}
+
+		if detail > detailBody {
+			continue
+		}
+
+		if body := test.Details().Body; strings.TrimSpace(body) != "" && body != message {
+			markdownBlock(&buf, "", truncateLines(body, config.bodyLines))
+		}
+
+		if detail > detailOutput {
+			continue
+		}
+
+		for _, output := range []struct{ name, text string }{{"stdout", test.SystemOut}, {"stderr", test.SystemErr}} {
+			if strings.TrimSpace(output.text) != "" {
+				markdownBlock(&buf, output.name, truncateLines(output.text, config.bodyLines))
+			}
+		}
+	}
+
+	buf.WriteString("\n</details>\n")
+
+	return buf.Bytes()
+}
+
+// markdownBlock writes the given text as a fenced code block within a list
+// item, preceded by the given label if any.
+func markdownBlock(buf *bytes.Buffer, label, text string) {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+
+	buf.WriteByte('\n')
+
+	if label != "" {
+		fmt.Fprintf(buf, "  %s:\n\n", label)
+	}
+
+	fmt.Fprintf(buf, "  %s\n", fence)
+
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		fmt.Fprintf(buf, "  %s\n", line)
+	}
+
+	fmt.Fprintf(buf, "  %s\n", fence)
+}
+
+// markdownCode returns the given text as inline code.
+func markdownCode(text string) string {
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+
+	return fence + text + fence
+}
+
+// markdownEscape escapes characters in the given text that would otherwise
+// be interpreted as Markdown or HTML.
+func markdownEscape(text string) string {
+	var buf strings.Builder
+
+	for _, char := range text {
+		switch char {
+		case '<':
+			buf.WriteString("&lt;")
+		case '>':
+			buf.WriteString("&gt;")
+		case '&':
+			buf.WriteString("&amp;")
+		case '\\', '`', '*', '_', '[', ']', '#', '|', '~':
+			buf.WriteByte('\\')
+			buf.WriteRune(char)
+		default:
+			buf.WriteRune(char)
+		}
+	}
+
+	return buf.String()
+}
+
+// truncateLines returns the first given number of lines of the given text,
+// noting how many lines were omitted. A limit of zero is unlimited.
+func truncateLines(text string, limit int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if limit <= 0 || len(lines) <= limit {
+		return text
+	}
+
+	return strings.Join(lines[:limit], "\n") + fmt.Sprintf("\n… (%d more lines)", len(lines)-limit)
+}
@@ -0,0 +1,136 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/joshdk/go-junit"
+)
+
+const markdownTotals = `## Test Results
+
+| Tests | Passed | Failed | Errors | Skipped | Duration |
+| ---: | ---: | ---: | ---: | ---: | ---: |
+| 5 | 2 | 1 | 1 | 1 | 3.503s |
+`
+
+func TestMarkdown(t *testing.T) {
+	expected := markdownTotals + "\n<details>\n<summary>2 failed tests</summary>\n\n" +
+		"- **failed** `outer > inner > pkg.Inner > fails <badly>` (1.5s)\n\n" +
+		"  expected true\n\n" +
+		"  ```\n  AssertionError: expected true\n  \tat inner_test.go:12\n  ```\n\n" +
+		"  stdout:\n\n  ```\n  some output\n  ```\n\n" +
+		"- **error** `outer > inner > errors` (3ms)\n\n" +
+		"  ```\n  panic: boom\n  ```\n\n" +
+		"</details>\n"
+
+	var buf bytes.Buffer
+	assertNoError(t, Markdown(&buf, example()))
+
+	assertEqual(t, expected, buf.String())
+}
+
+func TestMarkdownErrorPointer(t *testing.T) {
+	suites := example()
+
+	// Errors recorded by pointer are described in the same way, including a
+	// message taken from the error when the test has none.
+	test := &suites[0].Suites[0].Tests[0]
+	details := test.Details()
+	test.Message, test.Error = "", &details
+
+	var expected, actual bytes.Buffer
+	assertNoError(t, Markdown(&expected, example()))
+
+	assertNoError(t, Markdown(&actual, suites))
+
+	assertEqual(t, expected.String(), actual.String())
+}
+
+func TestMarkdownBudget(t *testing.T) {
+	suites := example()
+
+	// Add enough failures that all detail cannot fit.
+	inner := &suites[0].Suites[0]
+	for index := 0; index < 50; index++ {
+		inner.Tests = append(inner.Tests, junit.Test{
+			Name:      fmt.Sprintf("broken %d", index),
+			Status:    junit.StatusFailed,
+			Message:   "it broke",
+			Error:     junit.Error{Message: "it broke", Body: strings.Repeat("frame\n", 20)},
+			SystemOut: strings.Repeat("log line\n", 20),
+		})
+	}
+
+	render := func(budget int) string {
+		var buf bytes.Buffer
+		assertNoError(t, Markdown(&buf, suites, WithBudget(budget), WithBodyLines(5)))
+
+		if budget > 0 && buf.Len() > budget {
+			t.Fatalf("summary of %d bytes exceeds budget of %d bytes", buf.Len(), budget)
+		}
+
+		return buf.String()
+	}
+
+	full := render(0)
+	withoutOutput := render(len(full) - 1)
+	withoutBodies := render(len(withoutOutput) - 1)
+	namesOnly := render(len(withoutBodies) - 1)
+	truncated := render(len(namesOnly) - 1)
+
+	tests := []struct {
+		title      string
+		summary    string
+		expected   []string
+		unexpected []string
+	}{
+		{"full", full, []string{"stdout:", "  frame\n  … (15 more lines)\n", "  it broke\n"}, nil},
+		{"without output", withoutOutput, []string{"frame", "it broke"}, []string{"stdout:", "log line"}},
+		{"without bodies", withoutBodies, []string{"it broke"}, []string{"frame"}},
+		{"names only", namesOnly, []string{"- **failed** `outer > inner > broken 49` (0s)\n"}, []string{"it broke"}},
+		{"truncated", truncated, []string{"broken 48", "- …and 1 more\n"}, []string{"broken 49"}},
+	}
+
+	for _, test := range tests {
+		for _, expected := range test.expected {
+			if !strings.Contains(test.summary, expected) {
+				t.Fatalf("%s summary did not contain %q:\n%s", test.title, expected, test.summary)
+			}
+		}
+
+		for _, unexpected := range test.unexpected {
+			if strings.Contains(test.summary, unexpected) {
+				t.Fatalf("%s summary contained %q:\n%s", test.title, unexpected, test.summary)
+			}
+		}
+	}
+
+	// The totals are always included.
+	var buf bytes.Buffer
+	assertNoError(t, Markdown(&buf, suites, WithBudget(1)))
+
+	if totals := "| 55 | 2 | 51 | 1 | 1 | 3.503s |\n\n<details>\n<summary>52 failed tests</summary>\n\n- …and 52 more\n\n</details>\n"; !strings.Contains(buf.String(), totals) {
+		t.Fatalf("summary did not contain %q:\n%s", totals, buf.String())
+	}
+}
+
+func TestMarkdownPassed(t *testing.T) {
+	var buf bytes.Buffer
+	assertNoError(t, Markdown(&buf, example()[:0], WithHeading("")))
+
+	assertEqual(t, "| Tests | Passed | Failed | Errors | Skipped | Duration |\n| ---: | ---: | ---: | ---: | ---: | ---: |\n| 0 | 0 | 0 | 0 | 0 | 0s |\n", buf.String())
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	assertEqual(t, `a \*b\* &lt;c&gt; \[d\]`, markdownEscape("a *b* <c> [d]"))
+	assertEqual(t, "``a`b``", markdownCode("a`b"))
+	assertEqual(t, "`` `a ``", markdownCode("`a"))
+}
@@ -0,0 +1,115 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+// Package render exposes several library functions for presenting ingested
+// JUnit test suites to people, such as in CI artifacts and pull requests.
+package render
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+// statuses is every test status, in the order in which they are presented.
+var statuses = []junit.Status{ //nolint:gochecknoglobals
+	junit.StatusPassed,
+	junit.StatusFailed,
+	junit.StatusError,
+	junit.StatusSkipped,
+}
+
+// count returns the number of tests in the given totals with the given
+// status.
+func count(totals junit.Totals, status junit.Status) int {
+	switch status {
+	case junit.StatusPassed:
+		return totals.Passed
+	case junit.StatusFailed:
+		return totals.Failed
+	case junit.StatusError:
+		return totals.Error
+	case junit.StatusSkipped:
+		return totals.Skipped
+	default:
+		return 0
+	}
+}
+
+// failing reports if the given test resulted in a failure or an error.
+func failing(test junit.Test) bool {
+	return test.Status == junit.StatusFailed || test.Status == junit.StatusError
+}
+
+// suiteName returns a name for the given suite, falling back to its package
+// if it has no name.
+func suiteName(suite junit.Suite) string {
+	if suite.Name != "" {
+		return suite.Name
+	}
+
+	return suite.Package
+}
+
+// testName returns a name for the given test, which includes the names of the
+// suites that it is nested within.
+func testName(parents []junit.Suite, test junit.Test) string {
+	names := make([]string, 0, len(parents)+1)
+
+	for _, parent := range parents {
+		if name := suiteName(parent); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	if test.Classname != "" && (len(names) == 0 || names[len(names)-1] != test.Classname) {
+		names = append(names, test.Classname)
+	}
+
+	return strings.Join(append(names, test.Name), " > ")
+}
+
+// timed is a test, along with its full name.
+type timed struct {
+	name string
+	test junit.Test
+}
+
+// slowest returns the given number of tests that took the longest, in order
+// of decreasing duration.
+func slowest(suites []junit.Suite, limit int) []timed {
+	var tests []timed
+
+	junit.Walk(suites, func(parents []junit.Suite, test junit.Test) {
+		if test.Duration > 0 {
+			tests = append(tests, timed{testName(parents, test), test})
+		}
+	})
+
+	sort.SliceStable(tests, func(i, j int) bool {
+		return tests[i].test.Duration > tests[j].test.Duration
+	})
+
+	if len(tests) > limit {
+		tests = tests[:limit]
+	}
+
+	return tests
+}
+
+// formatDuration formats the given duration for display, with a precision
+// that suits its magnitude.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Minute:
+		return d.Round(time.Second).String()
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
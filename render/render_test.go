@@ -0,0 +1,99 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package render
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+// example returns suites with a test of every status, including a nested
+// suite.
+func example() []junit.Suite {
+	suites := []junit.Suite{
+		{
+			Name: "outer",
+			Tests: []junit.Test{
+				{Name: "passes", Classname: "pkg.Outer", Status: junit.StatusPassed, Duration: 2 * time.Second},
+				{Name: "skips", Classname: "pkg.Outer", Status: junit.StatusSkipped, Message: "not today"},
+			},
+			Suites: []junit.Suite{
+				{
+					Name: "inner",
+					Tests: []junit.Test{
+						{
+							Name:      "fails <badly>",
+							Classname: "pkg.Inner",
+							Status:    junit.StatusFailed,
+							Duration:  1500 * time.Millisecond,
+							Message:   "expected true",
+							Error: junit.Error{
+								Message: "expected true",
+								Type:    "AssertionError",
+								Body:    "AssertionError: expected true\n\tat inner_test.go:12",
+							},
+							SystemOut: "some output\n",
+						},
+						{
+							Name:     "errors",
+							Status:   junit.StatusError,
+							Duration: 3 * time.Millisecond,
+							Error:    junit.Error{Body: "panic: boom"},
+							Attempts: []junit.Attempt{{Status: junit.StatusError}},
+						},
+						{
+							Name:     "flaky",
+							Status:   junit.StatusPassed,
+							Duration: 250 * time.Microsecond,
+							Attempts: []junit.Attempt{{Status: junit.StatusFailed}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for index := range suites {
+		suites[index].Aggregate()
+	}
+
+	return suites
+}
+
+func TestTestName(t *testing.T) {
+	suites := example()
+
+	var names []string
+	junit.Walk(suites, func(parents []junit.Suite, test junit.Test) {
+		names = append(names, testName(parents, test))
+	})
+
+	assertEqual(t, []string{
+		"outer > pkg.Outer > passes",
+		"outer > pkg.Outer > skips",
+		"outer > inner > pkg.Inner > fails <badly>",
+		"outer > inner > errors",
+		"outer > inner > flaky",
+	}, names)
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		input    time.Duration
+		expected string
+	}{
+		{0, "0s"},
+		{1234567 * time.Nanosecond, "1.235ms"},
+		{1500 * time.Millisecond, "1.5s"},
+		{83250 * time.Millisecond, "1m23s"},
+	}
+
+	for _, test := range tests {
+		assertEqual(t, test.expected, formatDuration(test.input))
+	}
+}
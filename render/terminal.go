@@ -0,0 +1,221 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/joshdk/go-junit"
+)
+
+// ANSI escape sequences used for colour output.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiPurple = "\x1b[35m"
+)
+
+// TerminalOption configures the behavior of Terminal.
+type TerminalOption func(*terminalOptions)
+
+type terminalOptions struct {
+	color   *bool
+	compact bool
+}
+
+// WithColor configures whether the output is coloured. By default, output is
+// coloured if the writer is a terminal, and the NO_COLOR environment variable
+// is not set.
+func WithColor(color bool) TerminalOption {
+	return func(config *terminalOptions) {
+		config.color = &color
+	}
+}
+
+// WithCompact configures the output to include only failed and erroneous
+// tests, along with the suites that they are nested within.
+func WithCompact() TerminalOption {
+	return func(config *terminalOptions) {
+		config.compact = true
+	}
+}
+
+// Terminal writes the given suites to the given writer as a tree, suitable for
+// printing to a terminal.
+//
+// Each suite is printed with its nested suites and tests indented beneath it.
+// Each test is printed with a glyph for its status, its duration, and its
+// message if it failed, errored, or was skipped. A footer with the totals of
+// all suites is printed last.
+func Terminal(w io.Writer, suites []junit.Suite, opts ...TerminalOption) error {
+	var config terminalOptions
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	printer := terminalPrinter{
+		writer:  bufio.NewWriter(w),
+		color:   detectColor(w),
+		compact: config.compact,
+	}
+
+	if config.color != nil {
+		printer.color = *config.color
+	}
+
+	for _, suite := range suites {
+		printer.suite(suite, 0)
+	}
+
+	totals := junit.Sum(suites)
+
+	if len(suites) > 0 {
+		printer.writer.WriteByte('\n')
+	}
+
+	fmt.Fprintf(printer.writer, "%s %s, %s, %s, %s %s\n",
+		printer.paint(ansiBold, fmt.Sprintf("%d tests:", totals.Tests)),
+		printer.paint(ansiGreen, fmt.Sprintf("%d passed", totals.Passed)),
+		printer.paint(ansiRed, fmt.Sprintf("%d failed", totals.Failed)),
+		printer.paint(ansiPurple, fmt.Sprintf("%d errors", totals.Error)),
+		printer.paint(ansiYellow, fmt.Sprintf("%d skipped", totals.Skipped)),
+		printer.paint(ansiDim, "in "+formatDuration(totals.Duration)),
+	)
+
+	return printer.writer.Flush()
+}
+
+// detectColor reports if output to the given writer should be coloured.
+func detectColor(w io.Writer) bool {
+	if _, found := os.LookupEnv("NO_COLOR"); found || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+type terminalPrinter struct {
+	writer  *bufio.Writer
+	color   bool
+	compact bool
+}
+
+// paint returns the given text in the given style, if output is coloured.
+func (p terminalPrinter) paint(style, text string) string {
+	if !p.color {
+		return text
+	}
+
+	return style + text + ansiReset
+}
+
+// suite prints the given suite at the given depth.
+func (p terminalPrinter) suite(suite junit.Suite, depth int) {
+	suite.Aggregate()
+
+	if p.compact && suite.Totals.Failed+suite.Totals.Error == 0 {
+		return
+	}
+
+	indent := strings.Repeat("  ", depth)
+
+	name := suiteName(suite)
+	if name == "" {
+		name = "(unnamed suite)"
+	}
+
+	fmt.Fprintf(p.writer, "%s%s %s\n", indent, p.paint(ansiBold, name),
+		p.paint(ansiDim, fmt.Sprintf("(%d/%d passed, %s)", suite.Totals.Passed, suite.Totals.Tests, formatDuration(suite.Totals.Duration))))
+
+	for _, nested := range suite.Suites {
+		p.suite(nested, depth+1)
+	}
+
+	for _, test := range suite.Tests {
+		if p.compact && !failing(test) {
+			continue
+		}
+
+		p.test(test, suite, depth+1)
+	}
+}
+
+// test prints the given test, which is within the given suite, at the given
+// depth.
+func (p terminalPrinter) test(test junit.Test, suite junit.Suite, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	var glyph string
+
+	switch test.Status {
+	case junit.StatusPassed:
+		glyph = p.paint(ansiGreen, "✓")
+	case junit.StatusFailed:
+		glyph = p.paint(ansiRed, "✗")
+	case junit.StatusError:
+		glyph = p.paint(ansiPurple, "!")
+	case junit.StatusSkipped:
+		glyph = p.paint(ansiYellow, "-")
+	default:
+		glyph = "?"
+	}
+
+	name := test.Name
+	if test.Classname != "" && test.Classname != suiteName(suite) {
+		name = test.Classname + " > " + name
+	}
+
+	fmt.Fprintf(p.writer, "%s%s %s %s", indent, glyph, name, p.paint(ansiDim, "("+formatDuration(test.Duration)+")"))
+
+	if test.Flaky() {
+		fmt.Fprintf(p.writer, " %s", p.paint(ansiYellow, "[flaky]"))
+	}
+
+	p.writer.WriteByte('\n')
+
+	if test.Status == junit.StatusPassed {
+		return
+	}
+
+	message := strings.TrimSpace(test.Message)
+	if message == "" {
+		message = strings.TrimSpace(test.Details().Message)
+	}
+
+	// Otherwise, the first line of the error body often describes it.
+	if message == "" && test.Error != nil {
+		message = strings.SplitN(strings.TrimSpace(test.Error.Error()), "\n", 2)[0]
+	}
+
+	if message == "" {
+		return
+	}
+
+	style := ansiDim
+	if failing(test) {
+		style = ansiRed
+	}
+
+	for _, line := range strings.Split(message, "\n") {
+		fmt.Fprintf(p.writer, "%s    %s\n", indent, p.paint(style, line))
+	}
+}
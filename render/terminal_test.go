@@ -0,0 +1,90 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package render
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestTerminal(t *testing.T) {
+	tests := []struct {
+		title    string
+		options  []TerminalOption
+		expected string
+	}{
+		{
+			title: "tree",
+			expected: `outer (2/5 passed, 3.503s)
+  inner (1/3 passed, 1.503s)
+    ✗ pkg.Inner > fails <badly> (1.5s)
+        expected true
+    ! errors (3ms)
+        panic: boom
+    ✓ flaky (250µs) [flaky]
+  ✓ pkg.Outer > passes (2s)
+  - pkg.Outer > skips (0s)
+      not today
+
+5 tests: 2 passed, 1 failed, 1 errors, 1 skipped in 3.503s
+`,
+		},
+		{
+			title:   "compact",
+			options: []TerminalOption{WithCompact()},
+			expected: `outer (2/5 passed, 3.503s)
+  inner (1/3 passed, 1.503s)
+    ✗ pkg.Inner > fails <badly> (1.5s)
+        expected true
+    ! errors (3ms)
+        panic: boom
+
+5 tests: 2 passed, 1 failed, 1 errors, 1 skipped in 3.503s
+`,
+		},
+		{
+			title:   "color",
+			options: []TerminalOption{WithCompact(), WithColor(true)},
+			expected: "\x1b[1mouter\x1b[0m \x1b[2m(2/5 passed, 3.503s)\x1b[0m\n" +
+				"  \x1b[1minner\x1b[0m \x1b[2m(1/3 passed, 1.503s)\x1b[0m\n" +
+				"    \x1b[31m✗\x1b[0m pkg.Inner > fails <badly> \x1b[2m(1.5s)\x1b[0m\n" +
+				"        \x1b[31mexpected true\x1b[0m\n" +
+				"    \x1b[35m!\x1b[0m errors \x1b[2m(3ms)\x1b[0m\n" +
+				"        \x1b[31mpanic: boom\x1b[0m\n" +
+				"\n" +
+				"\x1b[1m5 tests:\x1b[0m \x1b[32m2 passed\x1b[0m, \x1b[31m1 failed\x1b[0m, \x1b[35m1 errors\x1b[0m, \x1b[33m1 skipped\x1b[0m \x1b[2min 3.503s\x1b[0m\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			var buf bytes.Buffer
+			assertNoError(t, Terminal(&buf, example(), test.options...))
+
+			assertEqual(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestDetectColor(t *testing.T) {
+	var buf bytes.Buffer
+	assertEqual(t, false, detectColor(&buf))
+
+	file, err := os.Open(os.DevNull)
+	assertNoError(t, err)
+	defer file.Close() //nolint
+
+	// The null device is a character device, so is treated as a terminal
+	// unless colour is disabled.
+	os.Unsetenv("NO_COLOR")
+	os.Setenv("TERM", "xterm")
+	assertEqual(t, true, detectColor(file))
+
+	os.Setenv("NO_COLOR", "")
+	defer os.Unsetenv("NO_COLOR")
+	assertEqual(t, false, detectColor(file))
+}
@@ -0,0 +1,479 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Frame is a single frame of a stack trace, identifying a function call and
+// its location in source. Any of its fields may be empty if they were not
+// included in the stack trace.
+type Frame struct {
+	// Function is the name of the function that was called, without its
+	// module, such as testStdoutStderr or (*T).Run.
+	Function string `json:"function,omitempty" yaml:"function,omitempty"`
+
+	// Module is the class, namespace, or package that contains the function,
+	// such as com.example.FooTest or github.com/joshdk/go-junit.
+	Module string `json:"module,omitempty" yaml:"module,omitempty"`
+
+	// File is the path of the source file that contains the call.
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+
+	// Line is the line number of the call within the source file.
+	Line int `json:"line,omitempty" yaml:"line,omitempty"`
+
+	// Column is the column number of the call within the source file.
+	Column int `json:"column,omitempty" yaml:"column,omitempty"`
+}
+
+// Exception is a single exception within a stack trace, along with the frames
+// of the call stack at which it was raised.
+type Exception struct {
+	// Type is the type of the exception, such as java.lang.AssertionError.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// Message is the message of the exception.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+
+	// Frames is the call stack at which the exception was raised, ordered
+	// from the frame that raised the exception to the outermost caller.
+	Frames []Frame `json:"frames,omitempty" yaml:"frames,omitempty"`
+}
+
+var (
+	// javaFramePattern matches a Java stack frame, such as:
+	//	at com.example.FooTest.testStdoutStderr(FooTest.java:13)
+	javaFramePattern = regexp.MustCompile(`^at (?:\S*/)?([\w$.]+)\.([\w$<>]+)\((Native Method|Unknown Source|[^\s():]+\.\w+(?::(\d+))?)\)$`) //nolint:gochecknoglobals
+
+	// dotnetFramePattern matches a .NET stack frame, such as:
+	//	at Calculator.CalculatorTests.Divide() in /src/CalculatorTests.cs:line 21
+	dotnetFramePattern = regexp.MustCompile(`^at (.+?)\.([^.\s(]+)\((.*)\)(?: in (.+):line (\d+))?$`) //nolint:gochecknoglobals
+
+	// jsFramePattern matches a JavaScript stack frame, such as:
+	//	at Context.<anonymous> (test/example.spec.js:10:5)
+	jsFramePattern = regexp.MustCompile(`^at (?:(?:async )?(.+?) \()?(\S.*?):(\d+):(\d+)\)?$`) //nolint:gochecknoglobals
+
+	// pythonFramePattern matches a Python traceback frame, such as:
+	//	File "tests/test_things.py", line 17, in test_failed
+	pythonFramePattern = regexp.MustCompile(`^File "([^"]+)", line (\d+)(?:, in (.+))?$`) //nolint:gochecknoglobals
+
+	// phpFramePattern matches a PHP stack frame, such as:
+	//	#0 /src/tests/SampleTest.php(18): SampleTest->testB()
+	phpFramePattern = regexp.MustCompile(`^#\d+ (?:(.+)\((\d+)\): )?(.+)$`) //nolint:gochecknoglobals
+
+	// goFilePattern matches the location of a Go stack frame, which follows
+	// the line naming its function, such as:
+	//	/src/example_test.go:12 +0x1d
+	goFilePattern = regexp.MustCompile(`^\t(\S.*?):(\d+)(?: \+0x[0-9a-f]+)?$`) //nolint:gochecknoglobals
+
+	// goLogPattern matches a line logged by a Go test, such as:
+	//	example_test.go:12: expected true
+	goLogPattern = regexp.MustCompile(`^(\S+\.go):(\d+)(?::(\d+))?: (.*)$`) //nolint:gochecknoglobals
+
+	// locationPattern matches a bare source location, such as:
+	//	/untitled/tests/SampleTest.php:18
+	locationPattern = regexp.MustCompile(`^(\S+\.\w+):(\d+)(?::(\d+))?$`) //nolint:gochecknoglobals
+
+	// exceptionPattern matches the header of an exception, such as:
+	//	java.lang.AssertionError: expected true
+	//	System.DivideByZeroException : Attempted to divide by zero.
+	exceptionPattern = regexp.MustCompile(`^([A-Za-z_$][\w$]*(?:[.\\][A-Za-z_$][\w$]*)*)(?: \[[\w-]+\])?(?:\s?:(?:\s+(.*))?)?$`) //nolint:gochecknoglobals
+
+	// exceptionSuffixPattern matches the unqualified names that are commonly
+	// given to exception types.
+	exceptionSuffixPattern = regexp.MustCompile(`(Error|Exception|Failure|Fault|Panic|Interrupt|Exit)$`) //nolint:gochecknoglobals
+)
+
+// ParseStackTrace parses the given text, which is typically the body of an
+// error, as a stack trace. Stack traces from Java, Python, Go, .NET,
+// JavaScript, and PHP are supported.
+//
+// Every exception within the stack trace is returned in the order that they
+// appear, including the causes of chained exceptions, such as those following
+// "Caused by:". Lines that are not part of a stack trace are ignored, and nil
+// is returned if the text contains no stack trace at all.
+func ParseStackTrace(text string) []Exception {
+	parser := stackParser{current: -1}
+
+	lines := strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n")
+
+	for index := 0; index < len(lines); index++ {
+		line := strings.TrimRight(lines[index], " \t\r")
+
+		// A Go stack frame spans two lines, the function and its location.
+		if index+1 < len(lines) && parser.goFrame(line, lines[index+1]) {
+			index++
+
+			continue
+		}
+
+		parser.line(line)
+	}
+
+	parser.flush()
+
+	return parser.exceptions
+}
+
+// StackTrace parses the body of the error as a stack trace, as described by
+// ParseStackTrace. If the stack trace does not name the type of its first
+// exception, then the type and message of the error are used instead.
+func (e Error) StackTrace() []Exception {
+	exceptions := ParseStackTrace(e.Body)
+	if len(exceptions) == 0 || exceptions[0].Type != "" {
+		return exceptions
+	}
+
+	first := &exceptions[0]
+
+	// Some frameworks, such as NUnit, include the exception type within the
+	// message.
+	lines := strings.SplitN(strings.TrimSpace(e.Message), "\n", 2)
+	if header, ok := parseException(lines[0], false); ok {
+		first.Type, first.Message = header.Type, header.Message
+
+		if len(lines) > 1 {
+			first.Message = strings.TrimSpace(first.Message + "\n" + lines[1])
+		}
+
+		return exceptions
+	}
+
+	first.Type = e.Type
+
+	if first.Message == "" {
+		first.Message = strings.TrimSpace(e.Message)
+	}
+
+	return exceptions
+}
+
+// stackParser holds the state of ParseStackTrace while parsing lines.
+type stackParser struct {
+	exceptions []Exception
+
+	// current is the index of the exception that frames are being added to,
+	// or -1 if there is none.
+	current int
+
+	// outer is the index of every exception that encloses an inner exception
+	// whose stack trace is not yet complete, as in .NET.
+	outer []int
+
+	// python is true while within a Python traceback, whose frames precede
+	// the exception that they belong to.
+	python bool
+
+	// pending is every frame of the current Python traceback.
+	pending []Frame
+
+	// continued is true if the following line may continue the message of
+	// the current exception.
+	continued bool
+}
+
+// line parses a single line of a stack trace.
+func (p *stackParser) line(line string) {
+	trimmed := strings.TrimSpace(line)
+
+	switch {
+	case trimmed == "":
+		p.continued = false
+
+		return
+
+	case trimmed == "Traceback (most recent call last):":
+		p.flush()
+		p.python = true
+
+		return
+
+	case strings.HasPrefix(trimmed, "--- End of inner exception stack trace"):
+		if n := len(p.outer); n > 0 {
+			p.current, p.outer = p.outer[n-1], p.outer[:n-1]
+		}
+
+		return
+
+	case trimmed == "Stack trace:",
+		strings.HasPrefix(trimmed, "goroutine ") && strings.HasSuffix(trimmed, ":"),
+		strings.HasPrefix(trimmed, "... ") && (strings.HasSuffix(trimmed, " more") || strings.HasSuffix(trimmed, " omitted")):
+		return
+	}
+
+	if frame, ok := parseFrame(trimmed); ok {
+		p.continued = false
+
+		if p.python {
+			p.pending = append(p.pending, frame)
+
+			return
+		}
+
+		if p.current < 0 {
+			p.add(Exception{})
+		}
+
+		p.exceptions[p.current].Frames = append(p.exceptions[p.current].Frames, frame)
+
+		return
+	}
+
+	// Python prints the source of each frame, indented beneath it.
+	if p.python && line != trimmed {
+		return
+	}
+
+	if match := goLogPattern.FindStringSubmatch(trimmed); match != nil && !p.python {
+		frame := Frame{File: match[1]}
+		frame.Line, _ = strconv.Atoi(match[2])
+		frame.Column, _ = strconv.Atoi(match[3])
+
+		p.add(Exception{Message: match[4], Frames: []Frame{frame}})
+		p.continued = true
+
+		return
+	}
+
+	if p.header(trimmed) {
+		return
+	}
+
+	if p.continued && p.current >= 0 {
+		exception := &p.exceptions[p.current]
+		exception.Message = strings.TrimLeft(exception.Message+"\n"+trimmed, "\n")
+	}
+}
+
+// header parses the given line as the header of one or more exceptions, and
+// reports if it was one.
+func (p *stackParser) header(line string) bool {
+	line = strings.TrimSuffix(line, " [recovered]")
+
+	for _, prefix := range []string{"Caused by: ", "Suppressed: ", "Uncaught ", "PHP Fatal error:  Uncaught "} {
+		line = strings.TrimPrefix(line, prefix)
+	}
+
+	if strings.HasPrefix(line, "Exception in thread ") {
+		if index := strings.Index(line[len("Exception in thread "):], `" `); index >= 0 {
+			line = line[len("Exception in thread ")+index+2:]
+		}
+	}
+
+	// .NET lists inner exceptions on the same line, separated by arrows.
+	parts := strings.Split(line, " ---> ")
+	headers := make([]Exception, 0, len(parts))
+
+	for _, part := range parts {
+		header, ok := parseException(strings.TrimSpace(part), p.python)
+		if !ok {
+			return false
+		}
+
+		headers = append(headers, header)
+	}
+
+	// A recovered Go panic repeats its header.
+	if len(headers) == 1 && p.current >= 0 {
+		previous := p.exceptions[p.current]
+		if previous.Type == headers[0].Type && previous.Message == headers[0].Message && len(previous.Frames) == 0 {
+			return true
+		}
+	}
+
+	for index, header := range headers {
+		if index > 0 {
+			p.outer = append(p.outer, p.current)
+		}
+
+		if p.python {
+			header.Frames = reverseFrames(p.pending)
+			p.pending, p.python = nil, false
+		}
+
+		p.add(header)
+	}
+
+	p.continued = true
+
+	return true
+}
+
+// add appends the given exception, and makes it the current exception.
+func (p *stackParser) add(exception Exception) {
+	p.exceptions = append(p.exceptions, exception)
+	p.current = len(p.exceptions) - 1
+}
+
+// flush adds the frames of an unterminated Python traceback as an exception
+// without a type.
+func (p *stackParser) flush() {
+	if p.python && len(p.pending) > 0 {
+		p.add(Exception{Frames: reverseFrames(p.pending)})
+	}
+
+	p.pending, p.python = nil, false
+}
+
+// reverseFrames reverses the order of the given frames, as Python lists the
+// frame that raised an exception last.
+func reverseFrames(frames []Frame) []Frame {
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+
+	return frames
+}
+
+// goFrame parses the given pair of lines as a Go stack frame, and reports if
+// they were one.
+func (p *stackParser) goFrame(line, next string) bool {
+	location := goFilePattern.FindStringSubmatch(strings.TrimRight(next, " \r"))
+	if location == nil || strings.HasPrefix(line, "\t") {
+		return false
+	}
+
+	// The goroutine that was created is followed by the call that created it.
+	call := line
+	if strings.HasPrefix(call, "created by ") {
+		call = strings.TrimPrefix(call, "created by ")
+
+		if index := strings.Index(call, " in goroutine "); index >= 0 {
+			call = call[:index]
+		}
+	} else if !strings.HasSuffix(call, ")") {
+		return false
+	}
+
+	// Trim the arguments of the call, which are themselves parenthesized.
+	if strings.HasSuffix(call, ")") {
+		if index := strings.LastIndex(call, "("); index > 0 {
+			call = call[:index]
+		}
+	}
+
+	frame := Frame{Function: call, File: location[1]}
+	frame.Line, _ = strconv.Atoi(location[2])
+
+	// The module is the package path, which ends at the first dot after the
+	// last slash.
+	slash := strings.LastIndex(call, "/")
+	if dot := strings.Index(call[slash+1:], "."); dot >= 0 {
+		frame.Module, frame.Function = call[:slash+1+dot], call[slash+2+dot:]
+	}
+
+	if p.current < 0 {
+		p.add(Exception{})
+	}
+
+	p.exceptions[p.current].Frames = append(p.exceptions[p.current].Frames, frame)
+	p.continued = false
+
+	return true
+}
+
+// parseFrame parses the given line as a single line stack frame, and reports
+// if it was one.
+func parseFrame(line string) (Frame, bool) {
+	var frame Frame
+
+	if match := pythonFramePattern.FindStringSubmatch(line); match != nil {
+		frame.File, frame.Function = match[1], match[3]
+		frame.Line, _ = strconv.Atoi(match[2])
+
+		return frame, true
+	}
+
+	// A .NET frame with a location is unambiguous, whereas one without a
+	// location could otherwise be mistaken for a Java frame.
+	if match := dotnetFramePattern.FindStringSubmatch(line); match != nil && match[4] != "" {
+		frame.Module, frame.Function, frame.File = match[1], match[2], match[4]
+		frame.Line, _ = strconv.Atoi(match[5])
+
+		return frame, true
+	}
+
+	if match := javaFramePattern.FindStringSubmatch(line); match != nil {
+		frame.Module, frame.Function = match[1], match[2]
+
+		if match[3] != "Native Method" && match[3] != "Unknown Source" {
+			frame.File = strings.SplitN(match[3], ":", 2)[0]
+			frame.Line, _ = strconv.Atoi(match[4])
+		}
+
+		return frame, true
+	}
+
+	if match := jsFramePattern.FindStringSubmatch(line); match != nil {
+		frame.Function, frame.File = match[1], match[2]
+		frame.Line, _ = strconv.Atoi(match[3])
+		frame.Column, _ = strconv.Atoi(match[4])
+
+		return frame, true
+	}
+
+	if match := dotnetFramePattern.FindStringSubmatch(line); match != nil {
+		frame.Module, frame.Function = match[1], match[2]
+
+		return frame, true
+	}
+
+	if match := phpFramePattern.FindStringSubmatch(line); match != nil {
+		frame.File = match[1]
+		frame.Line, _ = strconv.Atoi(match[2])
+		frame.Function = match[3]
+
+		if index := strings.Index(frame.Function, "("); index > 0 {
+			frame.Function = frame.Function[:index]
+		}
+
+		for _, separator := range []string{"->", "::"} {
+			if index := strings.LastIndex(frame.Function, separator); index >= 0 {
+				frame.Module, frame.Function = frame.Function[:index], frame.Function[index+len(separator):]
+
+				break
+			}
+		}
+
+		return frame, true
+	}
+
+	if match := locationPattern.FindStringSubmatch(line); match != nil {
+		frame.File = match[1]
+		frame.Line, _ = strconv.Atoi(match[2])
+		frame.Column, _ = strconv.Atoi(match[3])
+
+		return frame, true
+	}
+
+	return frame, false
+}
+
+// parseException parses the given line as the header of an exception, and
+// reports if it was one. Unless lenient, the type of the exception must be
+// qualified, or named like an exception.
+func parseException(line string, lenient bool) (Exception, bool) {
+	match := exceptionPattern.FindStringSubmatch(line)
+	if match == nil {
+		return Exception{}, false
+	}
+
+	name := match[1]
+
+	if name == "panic" {
+		return Exception{Type: name, Message: match[2]}, true
+	}
+
+	if !lenient && !strings.ContainsAny(name, `.\`) && !exceptionSuffixPattern.MatchString(name) {
+		return Exception{}, false
+	}
+
+	return Exception{Type: name, Message: match[2]}, true
+}
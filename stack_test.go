@@ -0,0 +1,307 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"testing"
+)
+
+func TestParseStackTrace(t *testing.T) {
+	tests := []struct {
+		title    string
+		input    string
+		expected []Exception
+	}{
+		{
+			title: "empty",
+		},
+		{
+			title: "not a stack trace",
+			input: "Assertion failed\nexpected: 1\n",
+		},
+		{
+			title: "java",
+			input: `java.lang.IllegalStateException: could not load
+	at com.example.Loader.load(Loader.java:21)
+	at java.base/jdk.internal.reflect.NativeMethodAccessorImpl.invoke0(Native Method)
+	at com.example.FooTest$Nested.test(FooTest.java:13)
+Caused by: java.io.FileNotFoundException: config.yaml
+(No such file or directory)
+	at java.io.FileInputStream.open0(Native Method)
+	at com.example.Loader.open(Unknown Source)
+	... 2 more
+`,
+			expected: []Exception{
+				{
+					Type:    "java.lang.IllegalStateException",
+					Message: "could not load",
+					Frames: []Frame{
+						{Function: "load", Module: "com.example.Loader", File: "Loader.java", Line: 21},
+						{Function: "invoke0", Module: "jdk.internal.reflect.NativeMethodAccessorImpl"},
+						{Function: "test", Module: "com.example.FooTest$Nested", File: "FooTest.java", Line: 13},
+					},
+				},
+				{
+					Type:    "java.io.FileNotFoundException",
+					Message: "config.yaml\n(No such file or directory)",
+					Frames: []Frame{
+						{Function: "open0", Module: "java.io.FileInputStream"},
+						{Function: "open", Module: "com.example.Loader"},
+					},
+				},
+			},
+		},
+		{
+			title: "python",
+			input: `Traceback (most recent call last):
+  File "tests/test_config.py", line 8, in load
+    return open(path)
+FileNotFoundError: [Errno 2] No such file or directory: 'config.yaml'
+
+The above exception was the direct cause of the following exception:
+
+Traceback (most recent call last):
+  File "tests/test_config.py", line 14, in test_load
+    load("config.yaml")
+    ~~~~^^^^^^^^^^^^^^^
+  File "tests/test_config.py", line 10, in load
+    raise ConfigError("could not load")
+ConfigError: could not load
+`,
+			expected: []Exception{
+				{
+					Type:    "FileNotFoundError",
+					Message: "[Errno 2] No such file or directory: 'config.yaml'",
+					Frames: []Frame{
+						{Function: "load", File: "tests/test_config.py", Line: 8},
+					},
+				},
+				{
+					Type:    "ConfigError",
+					Message: "could not load",
+					Frames: []Frame{
+						{Function: "load", File: "tests/test_config.py", Line: 10},
+						{Function: "test_load", File: "tests/test_config.py", Line: 14},
+					},
+				},
+			},
+		},
+		{
+			title: "go panic",
+			input: `panic: runtime error: index out of range [3] with length 3 [recovered]
+	panic: runtime error: index out of range [3] with length 3
+
+goroutine 7 [running]:
+testing.tRunner.func1.2({0x1043a0, 0xc000016108})
+	/usr/local/go/src/testing/testing.go:1545 +0x238
+github.com/joshdk/go-junit.(*parser).next(...)
+	/src/go-junit/parse.go:42
+github.com/joshdk/go-junit.TestParse(0xc000007860)
+	/src/go-junit/parse_test.go:17 +0x1d
+created by testing.(*T).Run in goroutine 1
+	/usr/local/go/src/testing/testing.go:1648 +0x3ad
+`,
+			expected: []Exception{
+				{
+					Type:    "panic",
+					Message: "runtime error: index out of range [3] with length 3",
+					Frames: []Frame{
+						{Function: "tRunner.func1.2", Module: "testing", File: "/usr/local/go/src/testing/testing.go", Line: 1545},
+						{Function: "(*parser).next", Module: "github.com/joshdk/go-junit", File: "/src/go-junit/parse.go", Line: 42},
+						{Function: "TestParse", Module: "github.com/joshdk/go-junit", File: "/src/go-junit/parse_test.go", Line: 17},
+						{Function: "(*T).Run", Module: "testing", File: "/usr/local/go/src/testing/testing.go", Line: 1648},
+					},
+				},
+			},
+		},
+		{
+			title: "go test",
+			input: "    parse_test.go:12: expected true\n    parse_test.go:15:3: unexpected\n        \tsecond line\n",
+			expected: []Exception{
+				{
+					Message: "expected true",
+					Frames:  []Frame{{File: "parse_test.go", Line: 12}},
+				},
+				{
+					Message: "unexpected\nsecond line",
+					Frames:  []Frame{{File: "parse_test.go", Line: 15, Column: 3}},
+				},
+			},
+		},
+		{
+			title: "dotnet",
+			input: "System.InvalidOperationException: Could not connect ---> System.TimeoutException : Timed out\r\n" +
+				"   at Example.Client.Connect(String host) in C:\\src\\Example\\Client.cs:line 30\r\n" +
+				"   --- End of inner exception stack trace ---\r\n" +
+				"   at Example.Tests.ClientTests.Connects() in C:\\src\\Example.Tests\\ClientTests.cs:line 12\r\n" +
+				"   at System.RuntimeMethodHandle.InvokeMethod(Object target, Span`1& arguments)\r\n",
+			expected: []Exception{
+				{
+					Type:    "System.InvalidOperationException",
+					Message: "Could not connect",
+					Frames: []Frame{
+						{Function: "Connects", Module: "Example.Tests.ClientTests", File: `C:\src\Example.Tests\ClientTests.cs`, Line: 12},
+						{Function: "InvokeMethod", Module: "System.RuntimeMethodHandle"},
+					},
+				},
+				{
+					Type:    "System.TimeoutException",
+					Message: "Timed out",
+					Frames: []Frame{
+						{Function: "Connect", Module: "Example.Client", File: `C:\src\Example\Client.cs`, Line: 30},
+					},
+				},
+			},
+		},
+		{
+			title: "javascript",
+			input: `AssertionError [ERR_ASSERTION]: expected 1 to equal 2
+    at Context.<anonymous> (test/example.spec.js:10:5)
+    at async Promise.all (index 0)
+    at processImmediate (node:internal/timers:476:21)
+    at test/setup.js:3:1
+`,
+			expected: []Exception{
+				{
+					Type:    "AssertionError",
+					Message: "expected 1 to equal 2",
+					Frames: []Frame{
+						{Function: "Context.<anonymous>", File: "test/example.spec.js", Line: 10, Column: 5},
+						{Function: "processImmediate", File: "node:internal/timers", Line: 476, Column: 21},
+						{File: "test/setup.js", Line: 3, Column: 1},
+					},
+				},
+			},
+		},
+		{
+			title: "php",
+			input: `PHP Fatal error:  Uncaught InvalidArgumentException: bad input in /src/Parser.php:12
+Stack trace:
+#0 /src/tests/ParserTest.php(18): App\Parser->parse('')
+#1 /src/vendor/phpunit/phpunit/src/Framework/TestCase.php(1154): ParserTest::testEmpty()
+#2 {main}
+`,
+			expected: []Exception{
+				{
+					Type:    "InvalidArgumentException",
+					Message: "bad input in /src/Parser.php:12",
+					Frames: []Frame{
+						{Function: "parse", Module: `App\Parser`, File: "/src/tests/ParserTest.php", Line: 18},
+						{Function: "testEmpty", Module: "ParserTest", File: "/src/vendor/phpunit/phpunit/src/Framework/TestCase.php", Line: 1154},
+						{Function: "{main}"},
+					},
+				},
+			},
+		},
+		{
+			title: "phpunit",
+			input: `SampleTest::testB
+    Failed asserting that false is true.
+
+    /untitled/tests/SampleTest.php:18
+`,
+			expected: []Exception{
+				{
+					Frames: []Frame{{File: "/untitled/tests/SampleTest.php", Line: 18}},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			assertEqual(t, test.expected, ParseStackTrace(test.input))
+		})
+	}
+}
+
+func TestErrorStackTrace(t *testing.T) {
+	tests := []struct {
+		title    string
+		input    Error
+		expected []Exception
+	}{
+		{
+			title: "type in body",
+			input: Error{
+				Type: "java.lang.AssertionError",
+				Body: "java.lang.AssertionError\n\tat com.example.FooTest.testStdoutStderr(FooTest.java:13)\n",
+			},
+			expected: []Exception{
+				{
+					Type:   "java.lang.AssertionError",
+					Frames: []Frame{{Function: "testStdoutStderr", Module: "com.example.FooTest", File: "FooTest.java", Line: 13}},
+				},
+			},
+		},
+		{
+			title: "type in message",
+			input: Error{
+				Type:    "Error",
+				Message: "System.DivideByZeroException : Attempted to divide by zero.",
+				Body:    "   at Calculator.Calculator.Divide(Int32 a, Int32 b) in /src/Calculator/Calculator.cs:line 12",
+			},
+			expected: []Exception{
+				{
+					Type:    "System.DivideByZeroException",
+					Message: "Attempted to divide by zero.",
+					Frames:  []Frame{{Function: "Divide", Module: "Calculator.Calculator", File: "/src/Calculator/Calculator.cs", Line: 12}},
+				},
+			},
+		},
+		{
+			title: "type of error",
+			input: Error{
+				Type:    `PHPUnit\Framework\ExpectationFailedException`,
+				Message: "Failed asserting that false is true.",
+				Body:    "SampleTest::testB\n\n/untitled/tests/SampleTest.php:18\n",
+			},
+			expected: []Exception{
+				{
+					Type:    `PHPUnit\Framework\ExpectationFailedException`,
+					Message: "Failed asserting that false is true.",
+					Frames:  []Frame{{File: "/untitled/tests/SampleTest.php", Line: 18}},
+				},
+			},
+		},
+		{
+			title: "no stack trace",
+			input: Error{Type: "AssertionError", Message: "expected true", Body: "expected true"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			assertEqual(t, test.expected, test.input.StackTrace())
+		})
+	}
+}
+
+func TestErrorStackTraceFile(t *testing.T) {
+	suites, err := IngestFile("testdata/nose2.xml")
+	assertNoError(t, err)
+
+	var exceptions []Exception
+
+	Walk(suites, func(_ []Suite, test Test) {
+		if test.Name == "test_params_method:2" {
+			exceptions = test.Error.(Error).StackTrace()
+		}
+	})
+
+	expected := []Exception{
+		{
+			Type:    "AssertionError",
+			Message: "2 != 1",
+			Frames: []Frame{
+				{Function: "test_params_method", File: "nose2/tests/functional/support/scenario/tests_in_package/pkg1/test/test_things.py", Line: 29},
+				{Function: "_method", File: "nose2/plugins/loader/parameters.py", Line: 144},
+			},
+		},
+	}
+
+	assertEqual(t, expected, exceptions)
+}
@@ -0,0 +1,377 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IngestTAP will parse the given TAP (Test Anything Protocol) data and return
+// a slice containing a single test suite definition. Both TAP version 13 and
+// version 14 are supported.
+//
+// Each test point ("ok" or "not ok") becomes a test, named by its
+// description. Tests with a SKIP directive are skipped, and failed tests with
+// a TODO directive are also considered skipped, since they are not expected to
+// pass. The reason given by a directive becomes the test message. A YAML
+// diagnostic block following a failed test becomes the body of its error, and
+// the "message" field of that block becomes the message of its error.
+//
+// Subtests become nested suites, named by their "# Subtest:" comment, or by
+// the test point that closes them. That test point is recorded as a test of
+// the subtest only if its outcome differs from that of the subtest's own
+// tests, such as for a failed subtest without any failed tests. Comments and
+// any other lines that are not part of the protocol are recorded as the
+// SystemOut of their suite. A "Bail out!" line is recorded as an erroneous
+// test, and ends ingestion.
+func IngestTAP(data []byte, opts ...Option) ([]Suite, error) {
+	return IngestTAPReader(bytes.NewReader(data), opts...)
+}
+
+// IngestTAPFile will parse the given TAP file and return a slice containing a
+// single test suite definition.
+func IngestTAPFile(filename string, opts ...Option) ([]Suite, error) {
+	file, err := os.Open(filename) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint
+
+	return IngestTAPReader(file, opts...)
+}
+
+// IngestTAPReader will parse the given TAP reader and return a slice
+// containing a single test suite definition.
+func IngestTAPReader(reader io.Reader, opts ...Option) ([]Suite, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := tapParser{
+		lines: strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"),
+	}
+
+	return newOptions(opts).finish([]Suite{parser.suite(0, "")})
+}
+
+var (
+	// tapPlanPattern matches a plan, such as "1..4" or "1..0 # SKIP reason".
+	tapPlanPattern = regexp.MustCompile(`^1\.\.(\d+)`) //nolint:gochecknoglobals
+
+	// tapTestPattern matches a test point, such as "not ok 2 - description".
+	tapTestPattern = regexp.MustCompile(`^(not )?ok\b(?:\s+(\d+))?(?:\s*-)?\s*(.*)$`) //nolint:gochecknoglobals
+
+	// tapDirectivePattern matches the directive of a test point, such as
+	// "SKIP reason" or "TODO reason".
+	tapDirectivePattern = regexp.MustCompile(`(?i)^(skip|todo)\S*\s*(.*)$`) //nolint:gochecknoglobals
+)
+
+// tapParser consumes the lines of a TAP document.
+type tapParser struct {
+	lines []string
+	pos   int
+
+	// bailed reports if a "Bail out!" line has been read, in which case the
+	// remainder of the document is ignored.
+	bailed bool
+}
+
+// suite consumes lines up until the first line that is indented less than
+// the given indent, and returns them as a suite with the given name.
+func (p *tapParser) suite(indent int, name string) Suite {
+	suite := Suite{Name: name}
+
+	var (
+		// subtest is the name given by a "# Subtest:" comment, for the
+		// subtest that is expected to follow.
+		subtest string
+
+		// pending is a subtest that has been read, but has not yet been
+		// closed by its test point.
+		pending *Suite
+	)
+
+	flush := func() {
+		if pending != nil {
+			suite.Suites = append(suite.Suites, *pending)
+			pending = nil
+		}
+	}
+
+	for p.pos < len(p.lines) && !p.bailed {
+		line := p.lines[p.pos]
+		if strings.TrimSpace(line) == "" {
+			p.pos++
+
+			continue
+		}
+
+		depth := tapIndent(line)
+		if depth < indent {
+			break
+		}
+
+		if depth > indent {
+			flush()
+
+			nested := p.suite(depth, subtest)
+			pending = &nested
+			subtest = ""
+
+			continue
+		}
+
+		p.pos++
+		content := line[depth:]
+
+		switch {
+		case strings.HasPrefix(content, "TAP version"), strings.HasPrefix(content, "pragma "):
+
+		case tapPlanPattern.MatchString(content):
+			// A plan only declares the number of tests, which is not enough
+			// to validate the suite, so it is not recorded.
+
+		case strings.HasPrefix(content, "Bail out!"):
+			reason := strings.TrimSpace(strings.TrimPrefix(content, "Bail out!"))
+			flush()
+			suite.Tests = append(suite.Tests, Test{
+				Name:    "Bail out!",
+				Status:  StatusError,
+				Message: reason,
+				Error:   Error{Message: reason},
+				Attempts: []Attempt{
+					{Status: StatusError, Message: reason},
+				},
+			})
+			p.bailed = true
+
+		case tapTestPattern.MatchString(content):
+			test := p.test(depth, content)
+
+			// A test point that directly follows a subtest closes it, and
+			// only contributes to the subtest itself, when its outcome is
+			// not already reflected by the tests of the subtest.
+			if pending != nil {
+				if pending.Name == "" {
+					pending.Name = test.Name
+				}
+
+				if tapOutcome(pending.Totals) != test.Status {
+					pending.Tests = append(pending.Tests, test)
+					pending.Aggregate()
+				}
+
+				flush()
+
+				break
+			}
+
+			suite.Tests = append(suite.Tests, test)
+
+		case strings.HasPrefix(content, "# Subtest:"):
+			flush()
+			subtest = strings.TrimSpace(strings.TrimPrefix(content, "# Subtest:"))
+
+		default:
+			suite.SystemOut += content + "\n"
+		}
+	}
+
+	flush()
+	suite.Aggregate()
+
+	return suite
+}
+
+// test builds a test from the given test point, and consumes any YAML
+// diagnostic block that follows it.
+func (p *tapParser) test(indent int, content string) Test {
+	match := tapTestPattern.FindStringSubmatch(content)
+	description, directive := tapSplitDirective(match[3])
+
+	test := Test{
+		Name:   description,
+		Status: StatusPassed,
+	}
+
+	if test.Name == "" {
+		test.Name = match[2]
+	}
+
+	if match[1] != "" {
+		test.Status = StatusFailed
+	}
+
+	if directive := tapDirectivePattern.FindStringSubmatch(directive); directive != nil {
+		switch strings.ToLower(directive[1]) {
+		case "skip":
+			test.Status = StatusSkipped
+			test.Message = directive[2]
+		case "todo":
+			if test.Status == StatusFailed {
+				test.Status = StatusSkipped
+			}
+			test.Message = directive[2]
+		}
+	}
+
+	diagnostics := p.diagnostics(indent)
+	if value := tapYAMLValue(diagnostics, "duration_ms"); value != "" {
+		if ms, err := strconv.ParseFloat(value, 64); err == nil {
+			test.Duration = time.Duration(ms * float64(time.Millisecond))
+		}
+	}
+
+	if test.Status == StatusFailed {
+		message := tapYAMLValue(diagnostics, "message")
+		test.Message = message
+		test.Error = Error{
+			Message: message,
+			Body:    diagnostics,
+		}
+		test.Attempts = []Attempt{{
+			Status:   StatusFailed,
+			Message:  message,
+			Body:     diagnostics,
+			Duration: test.Duration,
+		}}
+	}
+
+	return test
+}
+
+// diagnostics consumes the YAML diagnostic block that directly follows a test
+// point at the given indent, if there is one, and returns its dedented
+// content.
+func (p *tapParser) diagnostics(indent int) string {
+	if p.pos >= len(p.lines) {
+		return ""
+	}
+
+	start := p.lines[p.pos]
+	depth := tapIndent(start)
+
+	if depth <= indent || strings.TrimSpace(start) != "---" {
+		return ""
+	}
+
+	var lines []string
+
+	for p.pos++; p.pos < len(p.lines); p.pos++ {
+		line := p.lines[p.pos]
+		if tapIndent(line) == depth && strings.TrimSpace(line) == "..." {
+			p.pos++
+
+			break
+		}
+
+		if tapIndent(line) >= depth {
+			line = line[depth:]
+		} else {
+			line = strings.TrimLeft(line, " \t")
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// tapSplitDirective splits the given test point description at the first
+// unescaped "#", and returns the unescaped description, and the directive.
+func tapSplitDirective(text string) (string, string) {
+	var description strings.Builder
+
+	for index := 0; index < len(text); index++ {
+		switch char := text[index]; {
+		case char == '\\' && index+1 < len(text) && (text[index+1] == '#' || text[index+1] == '\\'):
+			index++
+			description.WriteByte(text[index])
+		case char == '#':
+			return strings.TrimSpace(description.String()), strings.TrimSpace(text[index+1:])
+		default:
+			description.WriteByte(char)
+		}
+	}
+
+	return strings.TrimSpace(description.String()), ""
+}
+
+// tapYAMLValue returns the scalar value of the given top-level key within the
+// given YAML block, or an empty string if there is no such key. Quoted values
+// and block scalars are supported, other structures are not.
+func tapYAMLValue(block, key string) string {
+	lines := strings.Split(block, "\n")
+
+	for index, line := range lines {
+		if !strings.HasPrefix(line, key+":") {
+			continue
+		}
+
+		value := strings.TrimSpace(strings.TrimPrefix(line, key+":"))
+
+		switch {
+		case strings.HasPrefix(value, "|"), strings.HasPrefix(value, ">"):
+			var content []string
+
+			for _, next := range lines[index+1:] {
+				if strings.TrimSpace(next) != "" && tapIndent(next) == 0 {
+					break
+				}
+				content = append(content, strings.TrimSpace(next))
+			}
+
+			separator := "\n"
+			if value[0] == '>' {
+				separator = " "
+			}
+
+			return strings.TrimSpace(strings.Join(content, separator))
+
+		case strings.HasPrefix(value, `"`):
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				return unquoted
+			}
+
+		case strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") && len(value) > 1:
+			return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+		}
+
+		return value
+	}
+
+	return ""
+}
+
+// tapOutcome returns the status implied by the given subtest totals, which is
+// failed if any test failed, and otherwise passed or skipped if any test
+// passed or was skipped, in that order.
+func tapOutcome(totals Totals) Status {
+	switch {
+	case totals.Failed+totals.Error > 0:
+		return StatusFailed
+	case totals.Passed > 0:
+		return StatusPassed
+	case totals.Skipped > 0:
+		return StatusSkipped
+	default:
+		return ""
+	}
+}
+
+// tapIndent returns the number of leading whitespace characters in the given
+// line.
+func tapIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
@@ -0,0 +1,137 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIngestTAPFile(t *testing.T) {
+	suites, err := IngestTAPFile("testdata/tap.tap")
+	assertNoError(t, err)
+	assertLen(t, suites, 1)
+
+	suite := suites[0]
+	assertEqual(t, Totals{}, suite.Declared)
+	assertEqual(t, "# starting the suite\n", suite.SystemOut)
+	assertLen(t, suite.Tests, 5)
+	assertLen(t, suite.Suites, 1)
+
+	assertEqual(t, "Input file opened", suite.Tests[0].Name)
+	assertEqual(t, StatusPassed, suite.Tests[0].Status)
+
+	assertEqual(t, StatusFailed, suite.Tests[1].Status)
+	assertEqual(t, "First line invalid", suite.Tests[1].Message)
+	assertEqual(t, 12500*time.Microsecond, suite.Tests[1].Duration)
+	assertError(t, suite.Tests[1].Error, "message: 'First line invalid'\nseverity: fail\ndata:\n  got: 'Flirble'\n  expect: 'Fnible'\nduration_ms: 12.5")
+
+	assertEqual(t, StatusSkipped, suite.Tests[3].Status)
+	assertEqual(t, "Not written yet", suite.Tests[3].Message)
+
+	assertEqual(t, "Checked the # of lines", suite.Tests[4].Name)
+	assertEqual(t, StatusSkipped, suite.Tests[4].Status)
+	assertEqual(t, "no line counter", suite.Tests[4].Message)
+
+	nested := suite.Suites[0]
+	assertEqual(t, "nested things", nested.Name)
+	assertEqual(t, Totals{}, nested.Declared)
+	assertLen(t, nested.Tests, 2)
+	assertEqual(t, "expected 1\ngot 2", nested.Tests[1].Message)
+	assertEqual(t, Totals{Tests: 2, Passed: 1, Failed: 1}, nested.Totals)
+
+	assertEqual(t, Totals{Tests: 7, Passed: 3, Skipped: 2, Failed: 2, Duration: 12500 * time.Microsecond}, suite.Totals)
+}
+
+func TestIngestTAP(t *testing.T) {
+	tests := []struct {
+		title string
+		input string
+		check func(*testing.T, Suite)
+	}{
+		{
+			title: "no descriptions",
+			input: "1..2\nok 1\nnot ok\n",
+			check: func(t *testing.T, suite Suite) {
+				assertLen(t, suite.Tests, 2)
+				assertEqual(t, "1", suite.Tests[0].Name)
+				assertEqual(t, "", suite.Tests[1].Name)
+				assertEqual(t, StatusFailed, suite.Tests[1].Status)
+			},
+		},
+		{
+			title: "todo passed",
+			input: "ok 1 - later # todo not expected to pass\n",
+			check: func(t *testing.T, suite Suite) {
+				assertEqual(t, StatusPassed, suite.Tests[0].Status)
+				assertEqual(t, "not expected to pass", suite.Tests[0].Message)
+			},
+		},
+		{
+			title: "bail out",
+			input: "1..3\nok 1 - first\nBail out! database is down\nok 2 - second\n",
+			check: func(t *testing.T, suite Suite) {
+				assertLen(t, suite.Tests, 2)
+				assertEqual(t, "Bail out!", suite.Tests[1].Name)
+				assertEqual(t, StatusError, suite.Tests[1].Status)
+				assertEqual(t, "database is down", suite.Tests[1].Message)
+			},
+		},
+		{
+			title: "unnamed subtest",
+			input: "    ok 1 - inner\n    1..1\nok 1 - outer\n",
+			check: func(t *testing.T, suite Suite) {
+				assertLen(t, suite.Tests, 0)
+				assertLen(t, suite.Suites, 1)
+				assertEqual(t, "outer", suite.Suites[0].Name)
+				assertLen(t, suite.Suites[0].Tests, 1)
+			},
+		},
+		{
+			title: "failed parent with passing subtests",
+			input: "# Subtest: outer\n    ok 1 - inner\n    1..2\nnot ok 1 - outer\n",
+			check: func(t *testing.T, suite Suite) {
+				assertLen(t, suite.Suites[0].Tests, 2)
+				assertEqual(t, "outer", suite.Suites[0].Tests[1].Name)
+				assertEqual(t, Totals{Tests: 2, Passed: 1, Failed: 1}, suite.Totals)
+			},
+		},
+		{
+			title: "passed parent without subtests",
+			input: "# Subtest: outer\n    1..0\nok 1 - outer\n",
+			check: func(t *testing.T, suite Suite) {
+				assertLen(t, suite.Suites[0].Tests, 1)
+				assertEqual(t, "outer", suite.Suites[0].Tests[0].Name)
+				assertEqual(t, Totals{Tests: 1, Passed: 1}, suite.Totals)
+			},
+		},
+		{
+			title: "skipped parent without subtests",
+			input: "# Subtest: outer\n    1..0\nok 1 - outer # SKIP not today\n",
+			check: func(t *testing.T, suite Suite) {
+				assertLen(t, suite.Suites[0].Tests, 1)
+				assertEqual(t, "not today", suite.Suites[0].Tests[0].Message)
+				assertEqual(t, Totals{Tests: 1, Skipped: 1}, suite.Totals)
+			},
+		},
+		{
+			title: "double quoted message",
+			input: "not ok 1\n  ---\n  message: \"tab\\there\"\n  ...\n",
+			check: func(t *testing.T, suite Suite) {
+				assertEqual(t, "tab\there", suite.Tests[0].Message)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			suites, err := IngestTAP([]byte(test.input))
+			assertNoError(t, err)
+			assertLen(t, suites, 1)
+			test.check(t, suites[0])
+		})
+	}
+}
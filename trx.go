@@ -0,0 +1,308 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IngestTRX will parse the given Visual Studio TRX data and return a slice of
+// test suite definitions, with one suite per test class.
+//
+// Each "UnitTestResult" tag becomes a test, named by its "testName" attribute,
+// with its class name taken from the matching "UnitTest" definition. Results
+// of data-driven tests are each recorded as a separate test. The "outcome"
+// attribute becomes the test status, where outcomes such as "NotExecuted" are
+// considered skipped, and outcomes such as "Timeout" are considered errors.
+// The "ErrorInfo" message and stack trace become the test message and error.
+func IngestTRX(data []byte, opts ...Option) ([]Suite, error) {
+	return IngestTRXReader(bytes.NewReader(data), opts...)
+}
+
+// IngestTRXFile will parse the given TRX file and return a slice of test suite
+// definitions.
+func IngestTRXFile(filename string, opts ...Option) ([]Suite, error) {
+	file, err := os.Open(filename) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint
+
+	return IngestTRXReader(file, opts...)
+}
+
+// IngestTRXReader will parse the given TRX reader and return a slice of test
+// suite definitions.
+func IngestTRXReader(reader io.Reader, opts ...Option) ([]Suite, error) {
+	config := newOptions(opts)
+
+	nodes, err := parse(reader, config)
+	if err != nil {
+		return nil, err
+	}
+
+	suites := make([]Suite, 0)
+
+	for _, node := range nodes {
+		if node.XMLName.Local != "TestRun" {
+			continue
+		}
+
+		run := ingestTestRun(node)
+
+		if config.strict {
+			if err := validateTestRun(node, run); err != nil {
+				return nil, err
+			}
+		}
+
+		suites = append(suites, run...)
+	}
+
+	return suites, nil
+}
+
+// validateTestRun compares the counts declared by the "Counters" tag of the
+// given "TestRun" tag against the given suites that were ingested from it.
+// Only the "total" and "passed" counts are compared, and only if they are
+// present. A *ValidationError is returned if there are any discrepancies.
+func validateTestRun(root xmlNode, suites []Suite) error {
+	var counters xmlNode
+
+	for _, node := range root.Nodes {
+		if node.XMLName.Local != "ResultSummary" {
+			continue
+		}
+
+		for _, child := range node.Nodes {
+			if child.XMLName.Local == "Counters" {
+				counters = child
+			}
+		}
+	}
+
+	totals := Sum(suites)
+
+	var discrepancies []Discrepancy
+
+	for _, count := range []struct {
+		attribute string
+		actual    int
+	}{
+		{"total", totals.Tests},
+		{"passed", totals.Passed},
+	} {
+		declared := counters.Attr(count.attribute)
+		if strings.TrimSpace(declared) == "" {
+			continue
+		}
+
+		if value, err := strconv.Atoi(strings.TrimSpace(declared)); err != nil || value != count.actual {
+			discrepancies = append(discrepancies, Discrepancy{
+				Attribute: count.attribute,
+				Declared:  declared,
+				Actual:    strconv.Itoa(count.actual),
+			})
+		}
+	}
+
+	if len(discrepancies) == 0 {
+		return nil
+	}
+
+	return &ValidationError{
+		Suite:         root.Attr("name"),
+		Discrepancies: discrepancies,
+	}
+}
+
+// ingestTestRun groups the results of a single "TestRun" tag into suites by
+// class name, in the order in which each class is first seen.
+func ingestTestRun(root xmlNode) []Suite {
+	var (
+		classnames = make(map[string]string)
+		suites     []Suite
+		indices    = make(map[string]int)
+	)
+
+	for _, node := range root.Nodes {
+		if node.XMLName.Local != "TestDefinitions" {
+			continue
+		}
+
+		for _, definition := range node.Nodes {
+			for _, method := range definition.Nodes {
+				if method.XMLName.Local == "TestMethod" {
+					classnames[definition.Attr("id")] = trxClassname(method.Attr("className"))
+				}
+			}
+		}
+	}
+
+	var add func(result xmlNode)
+
+	add = func(result xmlNode) {
+		for _, node := range result.Nodes {
+			if node.XMLName.Local != "InnerResults" {
+				continue
+			}
+
+			// Data-driven tests are recorded by their inner results.
+			for _, inner := range node.Nodes {
+				add(inner)
+			}
+
+			return
+		}
+
+		classname := classnames[result.Attr("testId")]
+
+		index, found := indices[classname]
+		if !found {
+			index = len(suites)
+			indices[classname] = index
+			suites = append(suites, Suite{Name: classname})
+		}
+
+		suite := &suites[index]
+		if suite.Hostname == "" {
+			suite.Hostname = result.Attr("computerName")
+		}
+
+		if started := timestamp(result.Attr("startTime")); !started.IsZero() && (suite.Timestamp.IsZero() || started.Before(suite.Timestamp)) {
+			suite.Timestamp = started
+		}
+
+		suite.Tests = append(suite.Tests, ingestTRXResult(result, classname))
+	}
+
+	for _, node := range root.Nodes {
+		if node.XMLName.Local != "Results" {
+			continue
+		}
+
+		for _, result := range node.Nodes {
+			if result.XMLName.Local == "UnitTestResult" {
+				add(result)
+			}
+		}
+	}
+
+	for index := range suites {
+		suites[index].Aggregate()
+	}
+
+	return suites
+}
+
+func ingestTRXResult(root xmlNode, classname string) Test {
+	test := Test{
+		Name:      root.Attr("testName"),
+		Classname: classname,
+		Duration:  trxDuration(root.Attr("duration")),
+		Status:    trxStatus(root.Attr("outcome")),
+	}
+
+	var details Error
+
+	for _, node := range root.Nodes {
+		if node.XMLName.Local != "Output" {
+			continue
+		}
+
+		for _, output := range node.Nodes {
+			switch output.XMLName.Local {
+			case "StdOut":
+				test.SystemOut = string(output.Content)
+			case "StdErr":
+				test.SystemErr = string(output.Content)
+			case "ErrorInfo":
+				for _, info := range output.Nodes {
+					switch info.XMLName.Local {
+					case "Message":
+						details.Message = string(info.Content)
+					case "StackTrace":
+						details.Body = string(info.Content)
+					}
+				}
+			}
+		}
+	}
+
+	test.Message = details.Message
+
+	if test.Status == StatusFailed || test.Status == StatusError {
+		test.Error = details
+		test.Attempts = []Attempt{{
+			Status:   test.Status,
+			Message:  details.Message,
+			Body:     details.Body,
+			Duration: test.Duration,
+		}}
+	}
+
+	return test
+}
+
+// trxStatus returns the status for the given TRX outcome. Outcomes which
+// describe a test that was not run are considered skipped, and outcomes
+// which describe a test that could not complete are considered errors.
+func trxStatus(outcome string) Status {
+	switch outcome {
+	case "Failed":
+		return StatusFailed
+	case "Error", "Timeout", "Aborted":
+		return StatusError
+	case "NotExecuted", "NotRunnable", "Inconclusive", "Pending", "Disconnected", "InProgress":
+		return StatusSkipped
+	default:
+		return StatusPassed
+	}
+}
+
+// trxClassname returns the class name from the given "className" attribute,
+// which may be qualified with an assembly, such as "Tests.Class, Tests,
+// Version=1.0.0.0".
+func trxClassname(classname string) string {
+	if index := strings.Index(classname, ","); index != -1 {
+		classname = classname[:index]
+	}
+
+	return strings.TrimSpace(classname)
+}
+
+// trxDuration parses a TRX duration, which is formatted like a .NET TimeSpan
+// such as "00:01:02.5000000", or "1.00:00:00" when longer than a day.
+func trxDuration(timespec string) time.Duration {
+	parts := strings.Split(strings.TrimSpace(timespec), ":")
+	if len(parts) != 3 {
+		return 0
+	}
+
+	hours := parts[0]
+	days := "0"
+
+	if index := strings.Index(hours, "."); index != -1 {
+		days, hours = hours[:index], hours[index+1:]
+	}
+
+	d, err := time.ParseDuration(days + "h")
+	if err != nil {
+		return 0
+	}
+
+	rest, err := time.ParseDuration(hours + "h" + parts[1] + "m" + parts[2] + "s")
+	if err != nil {
+		return 0
+	}
+
+	return 24*d + rest
+}
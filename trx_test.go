@@ -0,0 +1,79 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIngestTRXFile(t *testing.T) {
+	suites, err := IngestTRXFile("testdata/dotnet.trx")
+	assertNoError(t, err)
+	assertLen(t, suites, 2)
+
+	calculator := suites[0]
+	assertEqual(t, "Calculator.Tests.CalculatorTests", calculator.Name)
+	assertEqual(t, "buildhost", calculator.Hostname)
+	assertEqual(t, time.Date(2021, 3, 4, 10, 11, 10, 900000000, time.UTC), calculator.Timestamp.UTC())
+	assertLen(t, calculator.Tests, 2)
+	assertEqual(t, "Add_ReturnsSum", calculator.Tests[0].Name)
+	assertEqual(t, "Calculator.Tests.CalculatorTests", calculator.Tests[0].Classname)
+	assertEqual(t, StatusPassed, calculator.Tests[0].Status)
+	assertEqual(t, 1234500*time.Nanosecond, calculator.Tests[0].Duration)
+	assertEqual(t, "adding numbers", calculator.Tests[0].SystemOut)
+	assertEqual(t, StatusFailed, calculator.Tests[1].Status)
+	assertEqual(t, "Assert.Throws() Failure\r\nExpected: typeof(System.DivideByZeroException)\r\nActual:   (No exception was thrown)", calculator.Tests[1].Message)
+	assertError(t, calculator.Tests[1].Error, "   at Calculator.Tests.CalculatorTests.Divide_ByZero_Throws() in /src/Calculator.Tests/CalculatorTests.cs:line 27")
+	assertEqual(t, "warning: slow", calculator.Tests[1].SystemErr)
+	assertLen(t, calculator.Tests[1].Attempts, 1)
+	assertEqual(t, Totals{Tests: 2, Passed: 1, Failed: 1, Duration: 1501234500 * time.Nanosecond}, calculator.Totals)
+
+	parser := suites[1]
+	assertEqual(t, "Calculator.Tests.ParserTests", parser.Name)
+	assertLen(t, parser.Tests, 3)
+	assertEqual(t, StatusSkipped, parser.Tests[0].Status)
+	assertEqual(t, "Not implemented yet", parser.Tests[0].Message)
+	assertEqual(t, nil, parser.Tests[0].Error)
+	assertEqual(t, "Format_Rows (Data Row 0)", parser.Tests[1].Name)
+	assertEqual(t, StatusError, parser.Tests[2].Status)
+	assertEqual(t, Totals{Tests: 3, Passed: 1, Skipped: 1, Error: 1, Duration: 200 * time.Millisecond}, parser.Totals)
+}
+
+func TestWithTRX(t *testing.T) {
+	suites, err := IngestDir("testdata")
+	assertNoError(t, err)
+
+	for _, suite := range suites {
+		if suite.Name == "Calculator.Tests.CalculatorTests" {
+			t.Fatal("expected TRX files to be ignored")
+		}
+	}
+
+	withTRX, err := IngestDir("testdata", WithTRX())
+	assertNoError(t, err)
+	assertLen(t, withTRX, len(suites)+2)
+}
+
+func TestTRXDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"00:00:00", 0},
+		{"00:00:00.0000001", 100 * time.Nanosecond},
+		{"01:02:03.5", time.Hour + 2*time.Minute + 3500*time.Millisecond},
+		{"1.00:00:01", 24*time.Hour + time.Second},
+		{"", 0},
+		{"garbage", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			assertEqual(t, test.expected, trxDuration(test.input))
+		})
+	}
+}
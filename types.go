@@ -69,6 +69,24 @@ type Suite struct {
 	// Package is an additional descriptor for the hierarchy of the suite.
 	Package string `json:"package" yaml:"package"`
 
+	// ID is an identifier given to the suite. Purpose and values differ by
+	// framework.
+	ID string `json:"id,omitempty" yaml:"id,omitempty"`
+
+	// Hostname is the name of the host on which the tests were run.
+	Hostname string `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+
+	// Timestamp is the time at which the tests were started.
+	Timestamp time.Time `json:"timestamp,omitempty" yaml:"timestamp,omitempty"`
+
+	// Declared is the counts and time as declared by the suite itself, rather
+	// than calculated from its tests. These may disagree with Totals if the
+	// report is incomplete. Passed is derived from the other declared counts.
+	Declared Totals `json:"declared" yaml:"declared"`
+
+	// Attributes is a mapping of all XML node attributes given to the suite.
+	Attributes map[string]string `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+
 	// Properties is a mapping of key-value pairs that were available when the
 	// tests were run.
 	Properties map[string]string `json:"properties,omitempty" yaml:"properties,omitempty"`
@@ -93,36 +111,66 @@ type Suite struct {
 
 // Aggregate calculates result sums across all tests and nested suites.
 func (s *Suite) Aggregate() {
-	totals := Totals{Tests: len(s.Tests)}
+	var totals Totals
 
 	for _, test := range s.Tests {
-		totals.Duration += test.Duration
-		switch test.Status {
-		case StatusPassed:
-			totals.Passed++
-		case StatusSkipped:
-			totals.Skipped++
-		case StatusFailed:
-			totals.Failed++
-		case StatusError:
-			totals.Error++
-		}
+		totals = totals.add(test.totals())
 	}
 
 	// just summing totals from nested suites
 	for _, suite := range s.Suites {
 		suite.Aggregate()
-		totals.Tests += suite.Totals.Tests
-		totals.Duration += suite.Totals.Duration
-		totals.Passed += suite.Totals.Passed
-		totals.Skipped += suite.Totals.Skipped
-		totals.Failed += suite.Totals.Failed
-		totals.Error += suite.Totals.Error
+		totals = totals.add(suite.Totals)
 	}
 
 	s.Totals = totals
 }
 
+// Walk calls the given function with every test in the given suites, and in
+// all of their nested suites, in order. Each test is accompanied by the chain
+// of suites that it is nested within, ordered from outermost to innermost.
+func Walk(suites []Suite, fn func(parents []Suite, test Test)) {
+	walk(nil, suites, fn)
+}
+
+func walk(parents []Suite, suites []Suite, fn func(parents []Suite, test Test)) {
+	for _, suite := range suites {
+		// Always copy the chain of parents, so that it can be safely retained.
+		chain := append(parents[:len(parents):len(parents)], suite)
+
+		for _, test := range suite.Tests {
+			fn(chain, test)
+		}
+
+		walk(chain, suite.Suites, fn)
+	}
+}
+
+// Sum returns the combined totals of the given suites, after aggregating the
+// totals of each suite from its tests. The given suites are not modified.
+func Sum(suites []Suite) Totals {
+	var totals Totals
+
+	for _, suite := range suites {
+		suite.Aggregate()
+		totals = totals.add(suite.Totals)
+	}
+
+	return totals
+}
+
+// add returns the sum of both totals.
+func (t Totals) add(other Totals) Totals {
+	return Totals{
+		Tests:    t.Tests + other.Tests,
+		Passed:   t.Passed + other.Passed,
+		Skipped:  t.Skipped + other.Skipped,
+		Failed:   t.Failed + other.Failed,
+		Error:    t.Error + other.Error,
+		Duration: t.Duration + other.Duration,
+	}
+}
+
 // Test represents the results of a single test run.
 type Test struct {
 	// Name is a descriptor given to the test.
@@ -149,6 +197,11 @@ type Test struct {
 	//   Error != nil && (Status == Failed || Status == Error)
 	Error error `json:"error" yaml:"error"`
 
+	// Attempts is an ordered record of every failed or erroneous execution of
+	// the test. Tests that were rerun after failing, such as with the Maven
+	// Surefire rerun feature, have an attempt for each execution.
+	Attempts []Attempt `json:"attempts,omitempty" yaml:"attempts,omitempty"`
+
 	// Additional properties from XML node attributes.
 	// Some tools use them to store additional information about test location.
 	Properties map[string]string `json:"properties" yaml:"properties"`
@@ -162,6 +215,75 @@ type Test struct {
 	SystemErr string `json:"stderr,omitempty" yaml:"stderr,omitempty"`
 }
 
+// Flaky reports if the test passed, but only after previously failing or
+// erroring when it was run.
+func (t Test) Flaky() bool {
+	return t.Status == StatusPassed && len(t.Attempts) > 0
+}
+
+// Details returns the error of the test as an Error, whether it was recorded
+// as an Error or as a pointer to one. Errors of other types are represented
+// using only their textual description as the body, and a test without an
+// error has empty details.
+func (t Test) Details() Error {
+	return errorDetails(t.Error)
+}
+
+// totals returns the results of this single test.
+func (t Test) totals() Totals {
+	totals := Totals{
+		Tests:    1,
+		Duration: t.Duration,
+	}
+
+	switch t.Status {
+	case StatusPassed:
+		totals.Passed++
+	case StatusSkipped:
+		totals.Skipped++
+	case StatusFailed:
+		totals.Failed++
+	case StatusError:
+		totals.Error++
+	}
+
+	return totals
+}
+
+// Attempt represents a single failed or erroneous execution of a test.
+type Attempt struct {
+	// Status is the result of the execution. Status values are failure &
+	// error.
+	Status Status `json:"status" yaml:"status"`
+
+	// Rerun reports if the execution was a rerun of the test, rather than its
+	// initial execution. Passed tests with rerun attempts are flaky.
+	Rerun bool `json:"rerun,omitempty" yaml:"rerun,omitempty"`
+
+	// Message is a descriptor given to the failure or error.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+
+	// Type is a descriptor given to the failure or error. Value is typically
+	// an exception class, such as an assertion.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// Body is extended text for the failure or error.
+	Body string `json:"body,omitempty" yaml:"body,omitempty"`
+
+	// StackTrace is the stacktrace of the failure or error, when given
+	// separately from the body.
+	StackTrace string `json:"stacktrace,omitempty" yaml:"stacktrace,omitempty"`
+
+	// SystemOut is textual output written to stdout during the execution.
+	SystemOut string `json:"stdout,omitempty" yaml:"stdout,omitempty"`
+
+	// SystemErr is textual output written to stderr during the execution.
+	SystemErr string `json:"stderr,omitempty" yaml:"stderr,omitempty"`
+
+	// Duration is the time taken by the execution.
+	Duration time.Duration `json:"duration,omitempty" yaml:"duration,omitempty"`
+}
+
 // Error represents an erroneous test result.
 type Error struct {
 	// Message is a descriptor given to the error. Purpose and values differ by
@@ -190,3 +312,18 @@ func (err Error) Error() string {
 		return err.Type
 	}
 }
+
+// errorDetails returns the given test error as an Error. Errors of other
+// types are represented using only their textual description as the body.
+func errorDetails(err error) Error {
+	switch err := err.(type) {
+	case nil:
+		return Error{}
+	case Error:
+		return err
+	case *Error:
+		return *err
+	default:
+		return Error{Body: err.Error()}
+	}
+}
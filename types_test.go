@@ -0,0 +1,80 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWalk(t *testing.T) {
+	suites, err := IngestFile("testdata/phpunit.xml")
+	assertNoError(t, err)
+
+	var paths []string
+	Walk(suites, func(parents []Suite, test Test) {
+		names := make([]string, 0, len(parents)+1)
+		for _, parent := range parents {
+			names = append(names, parent.Name)
+		}
+		paths = append(paths, strings.Join(append(names, test.Name), " > "))
+	})
+
+	assertEqual(t, []string{
+		"/untitled/tests > SampleTest > testA",
+		`/untitled/tests > SampleTest > SampleTest::testB > testB with data set "bool"`,
+		`/untitled/tests > SampleTest > SampleTest::testB > testB with data set "int"`,
+		`/untitled/tests > SampleTest > SampleTest::testB > testB with data set "string"`,
+		"/untitled/tests > SampleTest > SampleTest::testC > testC with data set #0",
+		"/untitled/tests > SampleTest > SampleTest::testC > testC with data set #1",
+		"/untitled/tests > SampleTest > SampleTest::testC > testC with data set #2",
+	}, paths)
+}
+
+func TestSum(t *testing.T) {
+	suites := []Suite{
+		{
+			Tests: []Test{
+				{Status: StatusPassed, Duration: time.Second},
+				{Status: StatusFailed},
+			},
+			Suites: []Suite{
+				{Tests: []Test{{Status: StatusSkipped}}},
+			},
+		},
+		{
+			Tests: []Test{{Status: StatusError, Duration: time.Second}},
+		},
+	}
+
+	assertEqual(t, Totals{Tests: 4, Passed: 1, Skipped: 1, Failed: 1, Error: 1, Duration: 2 * time.Second}, Sum(suites))
+
+	// The given suites are not modified.
+	assertEqual(t, Totals{}, suites[0].Totals)
+}
+
+func TestTestDetails(t *testing.T) {
+	details := Error{Message: "expected true", Type: "AssertionError", Body: "at example.go:12"}
+
+	tests := []struct {
+		title    string
+		err      error
+		expected Error
+	}{
+		{title: "none"},
+		{title: "error", err: details, expected: details},
+		{title: "pointer", err: &details, expected: details},
+		{title: "other", err: errors.New("boom"), expected: Error{Body: "boom"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			assertEqual(t, test.expected, Test{Error: test.err}.Details())
+		})
+	}
+}
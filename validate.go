@@ -0,0 +1,175 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Discrepancy represents a count or time declared by a suite, which disagrees
+// with the tests that the suite actually contains.
+type Discrepancy struct {
+	// Attribute is the name of the suite attribute, such as "tests" or
+	// "failures".
+	Attribute string `json:"attribute" yaml:"attribute"`
+
+	// Declared is the value of the attribute, as given in the report.
+	Declared string `json:"declared" yaml:"declared"`
+
+	// Actual is the value calculated from the tests that were ingested.
+	Actual string `json:"actual" yaml:"actual"`
+}
+
+// ValidationError is returned when strict ingestion encounters a suite whose
+// declared counts or time disagree with the tests that it contains. This
+// typically happens when a test runner crashes while writing a report.
+type ValidationError struct {
+	// Suite is the name of the suite that failed validation.
+	Suite string `json:"suite" yaml:"suite"`
+
+	// Discrepancies is every declared value that failed validation.
+	Discrepancies []Discrepancy `json:"discrepancies" yaml:"discrepancies"`
+}
+
+// Error returns a textual description of all discrepancies.
+func (err *ValidationError) Error() string {
+	descriptions := make([]string, len(err.Discrepancies))
+	for index, discrepancy := range err.Discrepancies {
+		descriptions[index] = fmt.Sprintf("declared %s=%q but found %s",
+			discrepancy.Attribute, discrepancy.Declared, discrepancy.Actual)
+	}
+
+	return fmt.Sprintf("suite %q is inconsistent: %s", err.Suite, strings.Join(descriptions, ", "))
+}
+
+// Validate compares the counts and time declared in the suite attributes
+// against the suite totals, which are assumed to have already been
+// aggregated. Only attributes that are present and non-empty are compared. A
+// *ValidationError is returned if there are any discrepancies.
+//
+// Suites that have no attributes, such as those ingested from formats other
+// than JUnit XML, have their Declared counts and time compared instead, unless
+// nothing was declared. Since such formats may count errors as failures, the
+// failed and erroneous counts are compared together.
+func (s Suite) Validate() error {
+	if s.Attributes == nil {
+		return s.validateDeclared()
+	}
+
+	var (
+		attrs  = s.Attributes
+		totals = s.Totals
+	)
+
+	var discrepancies []Discrepancy
+
+	counts := []struct {
+		attributes []string
+		actual     int
+	}{
+		{[]string{"tests"}, totals.Tests},
+		{[]string{"failures"}, totals.Failed},
+		{[]string{"errors"}, totals.Error},
+		{[]string{"skipped", "skips"}, totals.Skipped},
+	}
+
+	for _, count := range counts {
+		for _, attribute := range count.attributes {
+			declared := attrs[attribute]
+			if strings.TrimSpace(declared) == "" {
+				continue
+			}
+
+			if value, err := strconv.Atoi(strings.TrimSpace(declared)); err != nil || value != count.actual {
+				discrepancies = append(discrepancies, Discrepancy{
+					Attribute: attribute,
+					Declared:  declared,
+					Actual:    strconv.Itoa(count.actual),
+				})
+			}
+		}
+	}
+
+	if declared := attrs["time"]; strings.TrimSpace(declared) != "" {
+		if tooShort(duration(declared), totals) {
+			discrepancies = append(discrepancies, Discrepancy{
+				Attribute: "time",
+				Declared:  declared,
+				Actual:    formatDuration(totals.Duration),
+			})
+		}
+	}
+
+	if len(discrepancies) == 0 {
+		return nil
+	}
+
+	return &ValidationError{
+		Suite:         s.Name,
+		Discrepancies: discrepancies,
+	}
+}
+
+// validateDeclared compares the Declared counts and time of the suite against
+// the suite totals, as described by Validate.
+func (s Suite) validateDeclared() error {
+	declared, totals := s.Declared, s.Totals
+	if declared == (Totals{}) {
+		return nil
+	}
+
+	var discrepancies []Discrepancy
+
+	counts := []struct {
+		attribute        string
+		declared, actual int
+	}{
+		{"tests", declared.Tests, totals.Tests},
+		{"passed", declared.Passed, totals.Passed},
+		{"failed", declared.Failed + declared.Error, totals.Failed + totals.Error},
+		{"skipped", declared.Skipped, totals.Skipped},
+	}
+
+	for _, count := range counts {
+		if count.declared != count.actual {
+			discrepancies = append(discrepancies, Discrepancy{
+				Attribute: count.attribute,
+				Declared:  strconv.Itoa(count.declared),
+				Actual:    strconv.Itoa(count.actual),
+			})
+		}
+	}
+
+	if declared.Duration > 0 && tooShort(declared.Duration, totals) {
+		discrepancies = append(discrepancies, Discrepancy{
+			Attribute: "time",
+			Declared:  formatDuration(declared.Duration),
+			Actual:    formatDuration(totals.Duration),
+		})
+	}
+
+	if len(discrepancies) == 0 {
+		return nil
+	}
+
+	return &ValidationError{
+		Suite:         s.Name,
+		Discrepancies: discrepancies,
+	}
+}
+
+// tooShort reports if the given declared time is shorter than the tests in
+// the given totals took. A suite may legitimately take longer than its tests,
+// due to setup and teardown, but never less. Some leeway is given for
+// rounding.
+func tooShort(declared time.Duration, totals Totals) bool {
+	tolerance := time.Duration(totals.Tests+1) * time.Millisecond
+
+	return declared < totals.Duration-tolerance
+}
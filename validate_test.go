@@ -0,0 +1,138 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestWithStrict(t *testing.T) {
+	tests := []struct {
+		title    string
+		filename string
+		err      string
+	}{
+		{
+			title:    "consistent counts",
+			filename: "testdata/phpunit.xml",
+		},
+		{
+			title:    "alternate skipped attribute",
+			filename: "testdata/nose2.xml",
+		},
+		{
+			title:    "empty attributes",
+			filename: "testdata/cubic.xml",
+		},
+		{
+			title:    "inconsistent counts",
+			filename: "testdata/ibm.xml",
+			err:      `suite "COBOL Code Review" is inconsistent: declared tests="45" but found 1, declared failures="17" but found 1`,
+		},
+		{
+			title:    "inconsistent time",
+			filename: "testdata/surefire.xml",
+			err:      `suite "com.example.FooTest" is inconsistent: declared time="0.054" but found 1234.560`,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("#%d - %s", index+1, test.title)
+
+		t.Run(name, func(t *testing.T) {
+			_, err := IngestFile(test.filename)
+			assertNoError(t, err)
+
+			_, err = IngestFile(test.filename, WithStrict())
+			checkError(t, test.err, err)
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	input := []byte(`
+		<testsuite name="crashed" tests="3" failures="0" errors="0" skipped="0">
+			<testcase name="one" />
+			<testcase name="two"><failure /></testcase>
+		</testsuite>
+	`)
+
+	_, err := Ingest(input, WithStrict())
+
+	validationErr, ok := err.(*ValidationError)
+	assertEqual(t, true, ok)
+	assertEqual(t, &ValidationError{
+		Suite: "crashed",
+		Discrepancies: []Discrepancy{
+			{Attribute: "tests", Declared: "3", Actual: "2"},
+			{Attribute: "failures", Declared: "0", Actual: "1"},
+		},
+	}, validationErr)
+
+	err = IngestTestStream(bytes.NewReader(input), func([]Suite, Test) error {
+		return nil
+	}, WithStrict())
+	assertEqual(t, validationErr, err)
+}
+
+func TestWithStrictFormats(t *testing.T) {
+	for _, filename := range []string{
+		"testdata/ctrf.json",
+		"testdata/cucumber.json",
+		"testdata/dotnet.trx",
+		"testdata/go-test.json",
+		"testdata/nunit3.xml",
+		"testdata/tap.tap",
+		"testdata/xunit2.xml",
+	} {
+		t.Run(filename, func(t *testing.T) {
+			_, err := IngestFileAuto(filename, WithStrict())
+			assertNoError(t, err)
+		})
+	}
+
+	_, err := IngestFile("testdata/dotnet.trx", WithTRX(), WithStrict())
+	assertNoError(t, err)
+}
+
+func TestValidationErrorFormats(t *testing.T) {
+	tests := []struct {
+		title  string
+		ingest func(data []byte, opts ...Option) ([]Suite, error)
+		input  string
+		err    string
+	}{
+		{
+			title:  "nunit",
+			ingest: IngestNUnit,
+			input: `<test-run><test-suite type="TestFixture" name="Fixture" total="2" passed="2" failed="0" skipped="0" inconclusive="0">
+				<test-case name="one" result="Passed" />
+			</test-suite></test-run>`,
+			err: `suite "Fixture" is inconsistent: declared tests="2" but found 1, declared passed="2" but found 1`,
+		},
+		{
+			title:  "trx",
+			ingest: IngestTRX,
+			input: `<TestRun name="crashed">
+				<Results><UnitTestResult testId="t1" testName="one" outcome="Passed" /></Results>
+				<ResultSummary><Counters total="3" passed="1" /></ResultSummary>
+			</TestRun>`,
+			err: `suite "crashed" is inconsistent: declared total="3" but found 1`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			_, err := test.ingest([]byte(test.input))
+			assertNoError(t, err)
+
+			_, err = test.ingest([]byte(test.input), WithStrict())
+			checkError(t, test.err, err)
+		})
+	}
+}
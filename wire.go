@@ -0,0 +1,295 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// wireSuite is the encoding of a suite used by MarshalJSON and MarshalYAML,
+// which omits the timestamp if it is not known.
+type wireSuite struct {
+	Name       string            `json:"name" yaml:"name"`
+	Package    string            `json:"package" yaml:"package"`
+	ID         string            `json:"id,omitempty" yaml:"id,omitempty"`
+	Hostname   string            `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	Timestamp  *time.Time        `json:"timestamp,omitempty" yaml:"timestamp,omitempty"`
+	Declared   wireTotals        `json:"declared" yaml:"declared"`
+	Attributes map[string]string `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+	Properties map[string]string `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Tests      []wireTest        `json:"tests,omitempty" yaml:"tests,omitempty"`
+	Suites     []wireSuite       `json:"suites,omitempty" yaml:"suites,omitempty"`
+	SystemOut  string            `json:"stdout,omitempty" yaml:"stdout,omitempty"`
+	SystemErr  string            `json:"stderr,omitempty" yaml:"stderr,omitempty"`
+	Totals     wireTotals        `json:"totals" yaml:"totals"`
+}
+
+func encodeWireSuite(suite Suite) wireSuite {
+	encoded := wireSuite{
+		Name:       suite.Name,
+		Package:    suite.Package,
+		ID:         suite.ID,
+		Hostname:   suite.Hostname,
+		Declared:   encodeWireTotals(suite.Declared),
+		Attributes: suite.Attributes,
+		Properties: suite.Properties,
+		SystemOut:  suite.SystemOut,
+		SystemErr:  suite.SystemErr,
+		Totals:     encodeWireTotals(suite.Totals),
+	}
+
+	if !suite.Timestamp.IsZero() {
+		encoded.Timestamp = &suite.Timestamp
+	}
+
+	for _, test := range suite.Tests {
+		encoded.Tests = append(encoded.Tests, encodeWireTest(test))
+	}
+
+	for _, nested := range suite.Suites {
+		encoded.Suites = append(encoded.Suites, encodeWireSuite(nested))
+	}
+
+	return encoded
+}
+
+func (encoded wireSuite) decode() Suite {
+	suite := Suite{
+		Name:       encoded.Name,
+		Package:    encoded.Package,
+		ID:         encoded.ID,
+		Hostname:   encoded.Hostname,
+		Declared:   encoded.Declared.decode(),
+		Attributes: encoded.Attributes,
+		Properties: encoded.Properties,
+		SystemOut:  encoded.SystemOut,
+		SystemErr:  encoded.SystemErr,
+		Totals:     encoded.Totals.decode(),
+	}
+
+	if encoded.Timestamp != nil {
+		suite.Timestamp = *encoded.Timestamp
+	}
+
+	for _, test := range encoded.Tests {
+		suite.Tests = append(suite.Tests, test.decode())
+	}
+
+	for _, nested := range encoded.Suites {
+		suite.Suites = append(suite.Suites, nested.decode())
+	}
+
+	return suite
+}
+
+// wireTotals is the encoding of totals used by MarshalJSON and MarshalYAML,
+// with a textual duration.
+type wireTotals struct {
+	Tests    int          `json:"tests" yaml:"tests"`
+	Passed   int          `json:"passed" yaml:"passed"`
+	Skipped  int          `json:"skipped" yaml:"skipped"`
+	Failed   int          `json:"failed" yaml:"failed"`
+	Error    int          `json:"error" yaml:"error"`
+	Duration wireDuration `json:"duration" yaml:"duration"`
+}
+
+func encodeWireTotals(totals Totals) wireTotals {
+	return wireTotals{
+		Tests:    totals.Tests,
+		Passed:   totals.Passed,
+		Skipped:  totals.Skipped,
+		Failed:   totals.Failed,
+		Error:    totals.Error,
+		Duration: wireDuration(totals.Duration),
+	}
+}
+
+func (encoded wireTotals) decode() Totals {
+	return Totals{
+		Tests:    encoded.Tests,
+		Passed:   encoded.Passed,
+		Skipped:  encoded.Skipped,
+		Failed:   encoded.Failed,
+		Error:    encoded.Error,
+		Duration: time.Duration(encoded.Duration),
+	}
+}
+
+// wireTest is the encoding of a test used by MarshalJSON and MarshalYAML, with
+// a textual duration, and with its error encoded as an object.
+type wireTest struct {
+	Name       string         `json:"name" yaml:"name"`
+	Classname  string         `json:"classname" yaml:"classname"`
+	Duration   wireDuration   `json:"duration" yaml:"duration"`
+	Status     Status         `json:"status" yaml:"status"`
+	Message    string         `json:"message" yaml:"message"`
+	Error      *Error         `json:"error" yaml:"error"`
+	Attempts   []wireAttempt  `json:"attempts,omitempty" yaml:"attempts,omitempty"`
+	Properties wireProperties `json:"properties" yaml:"properties"`
+	SystemOut  string         `json:"stdout,omitempty" yaml:"stdout,omitempty"`
+	SystemErr  string         `json:"stderr,omitempty" yaml:"stderr,omitempty"`
+}
+
+func encodeWireTest(test Test) wireTest {
+	encoded := wireTest{
+		Name:       test.Name,
+		Classname:  test.Classname,
+		Duration:   wireDuration(test.Duration),
+		Status:     test.Status,
+		Message:    test.Message,
+		Properties: wireProperties(test.Properties),
+		SystemOut:  test.SystemOut,
+		SystemErr:  test.SystemErr,
+	}
+
+	if test.Error != nil {
+		details := errorDetails(test.Error)
+		encoded.Error = &details
+	}
+
+	for _, attempt := range test.Attempts {
+		encoded.Attempts = append(encoded.Attempts, encodeWireAttempt(attempt))
+	}
+
+	return encoded
+}
+
+func (encoded wireTest) decode() Test {
+	test := Test{
+		Name:       encoded.Name,
+		Classname:  encoded.Classname,
+		Duration:   time.Duration(encoded.Duration),
+		Status:     encoded.Status,
+		Message:    encoded.Message,
+		Properties: map[string]string(encoded.Properties),
+		SystemOut:  encoded.SystemOut,
+		SystemErr:  encoded.SystemErr,
+	}
+
+	if encoded.Error != nil {
+		test.Error = *encoded.Error
+	}
+
+	for _, attempt := range encoded.Attempts {
+		test.Attempts = append(test.Attempts, attempt.decode())
+	}
+
+	return test
+}
+
+// wireAttempt is the encoding of an attempt used by MarshalJSON and
+// MarshalYAML, with a textual duration.
+type wireAttempt struct {
+	Status     Status       `json:"status" yaml:"status"`
+	Rerun      bool         `json:"rerun,omitempty" yaml:"rerun,omitempty"`
+	Message    string       `json:"message,omitempty" yaml:"message,omitempty"`
+	Type       string       `json:"type,omitempty" yaml:"type,omitempty"`
+	Body       string       `json:"body,omitempty" yaml:"body,omitempty"`
+	StackTrace string       `json:"stacktrace,omitempty" yaml:"stacktrace,omitempty"`
+	SystemOut  string       `json:"stdout,omitempty" yaml:"stdout,omitempty"`
+	SystemErr  string       `json:"stderr,omitempty" yaml:"stderr,omitempty"`
+	Duration   wireDuration `json:"duration,omitempty" yaml:"duration,omitempty"`
+}
+
+func encodeWireAttempt(attempt Attempt) wireAttempt {
+	return wireAttempt{
+		Status:     attempt.Status,
+		Rerun:      attempt.Rerun,
+		Message:    attempt.Message,
+		Type:       attempt.Type,
+		Body:       attempt.Body,
+		StackTrace: attempt.StackTrace,
+		SystemOut:  attempt.SystemOut,
+		SystemErr:  attempt.SystemErr,
+		Duration:   wireDuration(attempt.Duration),
+	}
+}
+
+func (encoded wireAttempt) decode() Attempt {
+	return Attempt{
+		Status:     encoded.Status,
+		Rerun:      encoded.Rerun,
+		Message:    encoded.Message,
+		Type:       encoded.Type,
+		Body:       encoded.Body,
+		StackTrace: encoded.StackTrace,
+		SystemOut:  encoded.SystemOut,
+		SystemErr:  encoded.SystemErr,
+		Duration:   time.Duration(encoded.Duration),
+	}
+}
+
+// wireProperties are test properties, which are encoded as null rather than as
+// an empty mapping if there are none, so that they are decoded as nil.
+type wireProperties map[string]string
+
+func (p wireProperties) MarshalYAML() (interface{}, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	return map[string]string(p), nil
+}
+
+// wireDuration is a duration that is encoded as a string such as "1m2.5s".
+// It can be decoded from either a string, or a number of nanoseconds.
+type wireDuration time.Duration
+
+func (d wireDuration) MarshalJSON() ([]byte, error) {
+	return encodeJSON(time.Duration(d).String(), "")
+}
+
+func (d *wireDuration) UnmarshalJSON(data []byte) error {
+	var nanoseconds int64
+	if err := json.Unmarshal(data, &nanoseconds); err == nil {
+		*d = wireDuration(nanoseconds)
+
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q", text)
+	}
+
+	*d = wireDuration(parsed)
+
+	return nil
+}
+
+func (d wireDuration) MarshalYAML() (interface{}, error) {
+	return time.Duration(d).String(), nil
+}
+
+func (d *wireDuration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var nanoseconds int64
+	if err := unmarshal(&nanoseconds); err == nil {
+		*d = wireDuration(nanoseconds)
+
+		return nil
+	}
+
+	var text string
+	if err := unmarshal(&text); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q", text)
+	}
+
+	*d = wireDuration(parsed)
+
+	return nil
+}
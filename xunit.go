@@ -0,0 +1,211 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// IngestXUnit will parse the given xUnit.net v2 XML data and return a slice of
+// all contained test suite definitions.
+//
+// Each "assembly" tag becomes a suite, and each "collection" tag within it
+// becomes a nested suite. Each "test" tag becomes a test, with its "type"
+// attribute as the classname. The "result" attribute becomes the test status,
+// where tests that were not run are considered skipped. Failure messages and
+// skip reasons become the test message, and the failure exception type and
+// stack trace become the type and body of the test error. Traits become the
+// test properties. Errors reported outside of any test, such as failures
+// while cleaning up a fixture, are recorded as erroneous tests of their
+// assembly.
+func IngestXUnit(data []byte, opts ...Option) ([]Suite, error) {
+	return IngestXUnitReader(bytes.NewReader(data), opts...)
+}
+
+// IngestXUnitFile will parse the given xUnit.net v2 XML file and return a
+// slice of all contained test suite definitions.
+func IngestXUnitFile(filename string, opts ...Option) ([]Suite, error) {
+	file, err := os.Open(filename) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint
+
+	return IngestXUnitReader(file, opts...)
+}
+
+// IngestXUnitReader will parse the given xUnit.net v2 XML reader and return a
+// slice of all contained test suite definitions.
+func IngestXUnitReader(reader io.Reader, opts ...Option) ([]Suite, error) {
+	config := newOptions(opts)
+
+	nodes, err := parse(reader, config)
+	if err != nil {
+		return nil, err
+	}
+
+	suites := make([]Suite, 0)
+
+	var find func(nodes []xmlNode)
+
+	find = func(nodes []xmlNode) {
+		for _, node := range nodes {
+			switch node.XMLName.Local {
+			case "assembly":
+				suites = append(suites, ingestXUnitAssembly(node))
+			case "assemblies":
+				find(node.Nodes)
+			}
+		}
+	}
+
+	find(nodes)
+
+	return config.finish(suites)
+}
+
+func ingestXUnitAssembly(root xmlNode) Suite {
+	suite := Suite{
+		Name:      root.Attr("name"),
+		Timestamp: timestamp(root.Attr("run-date") + "T" + root.Attr("run-time")),
+		Declared:  attrTotals(root, "total", []string{"passed"}, []string{"failed"}, []string{"errors"}, []string{"skipped"}),
+	}
+
+	for _, node := range root.Nodes {
+		switch node.XMLName.Local {
+		case "collection":
+			suite.Suites = append(suite.Suites, ingestXUnitCollection(node))
+		case "errors":
+			for _, child := range node.Nodes {
+				if child.XMLName.Local == "error" {
+					suite.Tests = append(suite.Tests, ingestXUnitError(child))
+				}
+			}
+		}
+	}
+
+	// Each assembly error is recorded as an erroneous test, which the declared
+	// total does not include.
+	suite.Declared.Tests += suite.Declared.Error
+
+	suite.Aggregate()
+
+	return suite
+}
+
+func ingestXUnitCollection(root xmlNode) Suite {
+	suite := Suite{
+		Name:     root.Attr("name"),
+		Declared: attrTotals(root, "total", []string{"passed"}, []string{"failed"}, nil, []string{"skipped"}),
+	}
+
+	for _, node := range root.Nodes {
+		if node.XMLName.Local == "test" {
+			suite.Tests = append(suite.Tests, ingestXUnitTest(node))
+		}
+	}
+
+	suite.Aggregate()
+
+	return suite
+}
+
+func ingestXUnitTest(root xmlNode) Test {
+	test := Test{
+		Name:      root.Attr("name"),
+		Classname: root.Attr("type"),
+		Duration:  duration(root.Attr("time")),
+	}
+
+	var details Error
+
+	for _, node := range root.Nodes {
+		switch node.XMLName.Local {
+		case "failure":
+			details = xunitFailure(node)
+		case "reason":
+			details.Message = string(node.Content)
+		case "output":
+			test.SystemOut = string(node.Content)
+		case "traits":
+			test.Properties = joinedProperties(node, "trait")
+		}
+	}
+
+	switch root.Attr("result") {
+	case "Fail":
+		test.Status = StatusFailed
+	case "Skip", "NotRun":
+		test.Status = StatusSkipped
+	default:
+		test.Status = StatusPassed
+	}
+
+	test.Message = details.Message
+
+	if test.Status == StatusFailed {
+		test.Error = details
+		test.Attempts = []Attempt{{
+			Status:   test.Status,
+			Message:  details.Message,
+			Type:     details.Type,
+			Body:     details.Body,
+			Duration: test.Duration,
+		}}
+	}
+
+	return test
+}
+
+// ingestXUnitError records an error that was reported outside of any test as
+// an erroneous test, named after what caused the error, such as the name of a
+// class. The type of the error, such as "test-class-cleanup", becomes the
+// classname.
+func ingestXUnitError(root xmlNode) Test {
+	test := Test{
+		Name:      root.Attr("name"),
+		Classname: root.Attr("type"),
+		Status:    StatusError,
+	}
+
+	var details Error
+
+	for _, node := range root.Nodes {
+		if node.XMLName.Local == "failure" {
+			details = xunitFailure(node)
+		}
+	}
+
+	test.Message = details.Message
+	test.Error = details
+	test.Attempts = []Attempt{{
+		Status:  StatusError,
+		Message: details.Message,
+		Type:    details.Type,
+		Body:    details.Body,
+	}}
+
+	return test
+}
+
+func xunitFailure(root xmlNode) Error {
+	details := Error{
+		Type: root.Attr("exception-type"),
+	}
+
+	for _, node := range root.Nodes {
+		switch node.XMLName.Local {
+		case "message":
+			details.Message = string(node.Content)
+		case "stack-trace":
+			details.Body = string(node.Content)
+		}
+	}
+
+	return details
+}
@@ -0,0 +1,52 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIngestXUnitFile(t *testing.T) {
+	suites, err := IngestXUnitFile("testdata/xunit2.xml")
+	assertNoError(t, err)
+	assertLen(t, suites, 1)
+
+	assembly := suites[0]
+	assertEqual(t, "/src/Calculator.Tests/bin/Debug/net5.0/Calculator.Tests.dll", assembly.Name)
+	assertEqual(t, time.Date(2021, 3, 4, 10, 11, 12, 0, time.UTC), assembly.Timestamp)
+	assertEqual(t, Totals{Tests: 5, Passed: 2, Skipped: 1, Failed: 1, Error: 1, Duration: 534 * time.Millisecond}, assembly.Declared)
+	assertEqual(t, Totals{Tests: 5, Passed: 2, Skipped: 1, Failed: 1, Error: 1, Duration: 417123400 * time.Nanosecond}, assembly.Totals)
+
+	assertLen(t, assembly.Tests, 1)
+	assertEqual(t, "Calculator.Tests.DatabaseTests", assembly.Tests[0].Name)
+	assertEqual(t, "test-class-cleanup", assembly.Tests[0].Classname)
+	assertEqual(t, StatusError, assembly.Tests[0].Status)
+	assertEqual(t, "System.InvalidOperationException : Connection already closed", assembly.Tests[0].Message)
+
+	assertLen(t, assembly.Suites, 2)
+	collection := assembly.Suites[0]
+	assertEqual(t, "Test collection for Calculator.Tests.CalculatorTests", collection.Name)
+	assertEqual(t, 123*time.Millisecond, collection.Declared.Duration)
+	assertLen(t, collection.Tests, 3)
+
+	assertEqual(t, "Calculator.Tests.CalculatorTests.Add(a: 1, b: 2, expected: 3)", collection.Tests[0].Name)
+	assertEqual(t, "Calculator.Tests.CalculatorTests", collection.Tests[0].Classname)
+	assertEqual(t, StatusPassed, collection.Tests[0].Status)
+	assertEqual(t, 2123400*time.Nanosecond, collection.Tests[0].Duration)
+	assertEqual(t, map[string]string{"Category": "Fast,Math"}, collection.Tests[0].Properties)
+	assertEqual(t, "adding numbers\n", collection.Tests[0].SystemOut)
+
+	assertEqual(t, StatusFailed, collection.Tests[1].Status)
+	assertEqual(t, Error{
+		Message: "Assert.Equal() Failure\nExpected: 2\nActual:   3",
+		Type:    "Xunit.Sdk.EqualException",
+		Body:    "   at Calculator.Tests.CalculatorTests.Divide() in /src/Calculator.Tests/CalculatorTests.cs:line 27",
+	}, collection.Tests[1].Error)
+
+	assertEqual(t, StatusSkipped, collection.Tests[2].Status)
+	assertEqual(t, "Not implemented yet", collection.Tests[2].Message)
+}
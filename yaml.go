@@ -0,0 +1,63 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
+
+// IngestYAML will parse the given YAML data, as produced by MarshalYAML, and
+// return a slice of test suite definitions.
+func IngestYAML(data []byte, opts ...Option) ([]Suite, error) {
+	return IngestYAMLReader(bytes.NewReader(data), opts...)
+}
+
+// IngestYAMLFile will parse the given YAML file, as produced by MarshalYAML,
+// and return a slice of test suite definitions.
+func IngestYAMLFile(filename string, opts ...Option) ([]Suite, error) {
+	file, err := os.Open(filename) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint
+
+	return IngestYAMLReader(file, opts...)
+}
+
+// IngestYAMLReader will parse the given YAML reader, as produced by
+// MarshalYAML, and return a slice of test suite definitions. An empty
+// document contains no suites.
+func IngestYAMLReader(reader io.Reader, opts ...Option) ([]Suite, error) {
+	var encoded []wireSuite
+	if err := yaml.NewDecoder(reader).Decode(&encoded); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	suites := make([]Suite, len(encoded))
+	for index, suite := range encoded {
+		suites[index] = suite.decode()
+	}
+
+	return newOptions(opts).finish(suites)
+}
+
+// MarshalYAML returns the YAML encoding of the given suites.
+//
+// Suites are encoded using the same fields as MarshalJSON, with multi-line
+// text, such as test output, written as literal block scalars. Documents
+// produced by MarshalYAML can be ingested again by IngestYAML without loss.
+func MarshalYAML(suites []Suite) ([]byte, error) {
+	encoded := make([]wireSuite, len(suites))
+	for index, suite := range suites {
+		encoded[index] = encodeWireSuite(suite)
+	}
+
+	return yaml.Marshal(encoded)
+}
@@ -0,0 +1,172 @@
+// Copyright Josh Komoroske. All rights reserved.
+// Use of this source code is governed by the MIT license,
+// a copy of which can be found in the LICENSE.txt file.
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMarshalYAMLRoundTrip(t *testing.T) {
+	reports, err := IngestDirAuto("testdata")
+	assertNoError(t, err)
+
+	for _, report := range reports {
+		report := report
+
+		t.Run(filepath.Base(report.Filename), func(t *testing.T) {
+			data, err := MarshalYAML(report.Suites)
+			assertNoError(t, err)
+
+			actual, err := IngestYAML(data)
+			assertNoError(t, err)
+
+			// Timestamps are written with their offset, so only their location may
+			// differ.
+			assertEqual(t, utcTimestamps(report.Suites), utcTimestamps(actual))
+		})
+	}
+}
+
+func TestMarshalYAML(t *testing.T) {
+	suites := []Suite{
+		{
+			Name:       "suite",
+			Timestamp:  time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+			Properties: map[string]string{"line": "12", "go.version": "1.12"},
+			Tests: []Test{
+				{
+					Name:      "failed: badly",
+					Duration:  1500 * time.Millisecond,
+					Status:    StatusFailed,
+					SystemOut: "first\n\n  indented\n",
+					Error: Error{
+						Message: "expected true",
+						Body:    "at example.go:12",
+					},
+					Attempts: []Attempt{
+						{Status: StatusFailed, Duration: 90 * time.Second},
+					},
+				},
+			},
+			SystemErr: "no trailing newline\nhere",
+		},
+	}
+
+	expected := `- name: suite
+  package: ""
+  timestamp: 2021-01-02T03:04:05Z
+  declared:
+    tests: 0
+    passed: 0
+    skipped: 0
+    failed: 0
+    error: 0
+    duration: 0s
+  properties:
+    go.version: "1.12"
+    line: "12"
+  tests:
+  - name: 'failed: badly'
+    classname: ""
+    duration: 1.5s
+    status: failed
+    message: ""
+    error:
+      message: expected true
+      body: at example.go:12
+    attempts:
+    - status: failed
+      duration: 1m30s
+    properties: null
+    stdout: |
+      first
+
+        indented
+  stderr: |-
+    no trailing newline
+    here
+  totals:
+    tests: 0
+    passed: 0
+    skipped: 0
+    failed: 0
+    error: 0
+    duration: 0s
+`
+
+	actual, err := MarshalYAML(suites)
+	assertNoError(t, err)
+	assertEqual(t, expected, string(actual))
+
+	roundtrip, err := IngestYAML(actual)
+	assertNoError(t, err)
+	assertEqual(t, suites, roundtrip)
+}
+
+func TestIngestYAML(t *testing.T) {
+	suites, err := IngestYAML([]byte(`---
+# Written by hand.
+- name: 'it''s a suite'
+  timestamp: 2021-01-02T03:04:05Z
+  properties: {os: linux, "arch": amd64}
+  tests:
+  - name: first # trailing comment
+    status: passed
+    duration: 250ms
+  - {name: second, status: skipped, message: "tab\tand é", duration: 1500}
+  - name: third
+    status: error
+    error:
+      message: >
+        folded
+        message
+      body: |+
+        kept
+
+  stdout: |2
+      indented
+`))
+	assertNoError(t, err)
+	assertLen(t, suites, 1)
+
+	suite := suites[0]
+	assertEqual(t, "it's a suite", suite.Name)
+	assertEqual(t, time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC), suite.Timestamp)
+	assertEqual(t, map[string]string{"os": "linux", "arch": "amd64"}, suite.Properties)
+	assertEqual(t, "  indented\n", suite.SystemOut)
+	assertLen(t, suite.Tests, 3)
+	assertEqual(t, 250*time.Millisecond, suite.Tests[0].Duration)
+	assertEqual(t, 1500*time.Nanosecond, suite.Tests[1].Duration)
+	assertEqual(t, "tab\tand é", suite.Tests[1].Message)
+	assertEqual(t, Error{Message: "folded message\n", Body: "kept\n\n"}, suite.Tests[2].Error)
+}
+
+func TestIngestYAMLEmpty(t *testing.T) {
+	suites, err := IngestYAML(nil)
+	assertNoError(t, err)
+	assertLen(t, suites, 0)
+}
+
+func TestIngestYAMLErrors(t *testing.T) {
+	tests := []struct {
+		title    string
+		input    string
+		expected string
+	}{
+		{"unterminated quote", `- name: "suite`, "yaml: found unexpected end of stream"},
+		{"bad indentation", "- name: suite\n     package: pkg", "yaml: line 2: mapping values are not allowed in this context"},
+		{"invalid duration", "- totals: {duration: soon}", `invalid duration "soon"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			_, err := IngestYAML([]byte(test.input))
+			assertError(t, err, test.expected)
+		})
+	}
+}